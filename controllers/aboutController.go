@@ -1,13 +1,12 @@
 package controllers
 
 import (
-	"bytes"
-	"fmt"
 	"time"
 
 	"coding-kittens.com/models"
 	"coding-kittens.com/modules/articles"
-	"github.com/adrg/frontmatter"
+	"coding-kittens.com/modules/config"
+	"coding-kittens.com/modules/jsonld"
 	"github.com/gin-gonic/gin"
 )
 
@@ -18,37 +17,15 @@ func AboutController (c *gin.Context) map[string]interface{} {
 
 	difference := today.Sub(fromDate)
 
-	latestContent, err := articles.GetLatestContent(5)
+	latestArticles, err := articles.GetLatestContent(requestLanguage(c), 5)
 
 	if err != nil {
-		latestContent = []articles.FileInfo{}
-	}
-
-	var latestArticles []models.Article
-	for _, content := range latestContent {
-		var category string = content.Path[0];
-
-		file, err := ArticlesFS.ReadFile("web/_articles/" + category + "/" + content.FileName)
-		
-		if err != nil {
-			fmt.Println("Error reading file:", err)
-		}
-
-		var matter models.FrontMatter
-
-		frontmatter.MustParse(bytes.NewReader(file), &matter)
-
-		article := models.Article{
-			Slug:      content.FileName,
-			Category:  content.Path[0],
-			Data: matter,
-			
-		}
-		latestArticles = append(latestArticles, article)
+		latestArticles = []models.Article{}
 	}
 
 	return map[string]interface{}{
 		"yearDiff": int64(difference.Hours()/24/365),
 		"LatestContent": latestArticles,
+		"StructuredData": []interface{}{jsonld.NewPerson(config.Get())},
 	}
-}
\ No newline at end of file
+}
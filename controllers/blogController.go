@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"coding-kittens.com/models"
+	"coding-kittens.com/modules/articles"
+	"coding-kittens.com/modules/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+func BlogController(c *gin.Context) map[string]interface{} {
+	category := c.Query("category")
+	language := requestLanguage(c)
+
+	query := map[string]string{
+		"category": category,
+		"language": language,
+	}
+
+	snapshot := articles.Current().Language(language)
+
+	filters := url.Values{}
+	canonical := ""
+
+	// The filter lists the same articles as the page of the category
+	if category != "" {
+		if !snapshot.HasCategory(category) {
+			c.AbortWithStatus(http.StatusNotFound)
+			return nil
+		}
+
+		filters.Set("category", category)
+		canonical = i18n.Localize(language, models.CategoryURL(strings.Split(category, "/")))
+	}
+
+	allArticles := articles.GetAllArticles(query)
+
+	pageArticles, pagination, ok := paginate(allArticles, parsePage(c.Query("page")), i18n.Localize(language, "/blog"), filters)
+
+	if !ok {
+		c.AbortWithStatus(http.StatusNotFound)
+		return nil
+	}
+
+	if canonical == "" {
+		canonical = pagination.URL
+	}
+
+	return map[string]interface{}{
+		"Articles":   pageArticles,
+		"Pagination": pagination,
+		"Canonical":  canonical,
+		"Category":   category,
+		"Categories": snapshot.Subcategories(""),
+	}
+}
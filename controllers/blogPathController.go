@@ -0,0 +1,201 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"coding-kittens.com/models"
+	"coding-kittens.com/modules/articles"
+	"coding-kittens.com/modules/config"
+	"coding-kittens.com/modules/i18n"
+	"coding-kittens.com/modules/image"
+	"coding-kittens.com/modules/jsonld"
+	"coding-kittens.com/modules/preview"
+	"github.com/gin-gonic/gin"
+)
+
+// BlogPathController serves every page under /blog/*path: the article whose
+// category chain and slug match the path, the feed of a category, or else
+// the listing of the (possibly nested) category the path points at.
+func BlogPathController(c *gin.Context) map[string]interface{} {
+	segments := strings.Split(strings.Trim(c.Param("path"), "/"), "/")
+	language := requestLanguage(c)
+
+	if segments[0] == "" {
+		c.Redirect(http.StatusMovedPermanently, i18n.Localize(language, "/blog"))
+		c.Abort()
+		return nil
+	}
+
+	// Category feeds, e.g. /blog/css/feed.xml
+	if len(segments) > 1 && segments[len(segments)-1] == "feed.xml" {
+		categoryFeed(c, segments[:len(segments)-1])
+		return nil
+	}
+
+	snapshot := articles.Current().Language(language)
+
+	// Revisions of an article, e.g. /blog/css/slug/history
+	if len(segments) > 2 && segments[len(segments)-1] == "history" {
+		category := strings.Join(segments[:len(segments)-2], "/")
+
+		if article, ok := snapshot.Get(category, segments[len(segments)-2]); ok && snapshot.HasHistory(article) {
+			return historyPage(c, snapshot, article)
+		}
+	}
+
+	if len(segments) > 1 {
+		category := strings.Join(segments[:len(segments)-1], "/")
+
+		slug := segments[len(segments)-1]
+
+		if article, ok := snapshot.Get(category, slug); ok {
+			return articlePage(snapshot, article)
+		}
+
+		// Drafts and scheduled articles are only shown through signed links
+		if article, ok := snapshot.Unpublished(category, slug); ok && preview.Verify(article.URL(), c.Query(preview.QUERY_PARAMETER)) {
+			c.Header("X-Robots-Tag", "noindex, nofollow")
+			c.Header("Cache-Control", "private, no-store")
+
+			return articlePage(snapshot, article)
+		}
+	}
+
+	category := strings.Join(segments, "/")
+
+	if !snapshot.HasCategory(category) {
+		c.AbortWithStatus(http.StatusNotFound)
+		return nil
+	}
+
+	var subcategories []models.Breadcrumb
+	for _, subcategory := range snapshot.Subcategories(category) {
+		chain := strings.Split(subcategory, "/")
+		subcategories = append(subcategories, models.Breadcrumb{
+			Name: chain[len(chain)-1],
+			URL:  i18n.Localize(language, models.CategoryURL(chain)),
+		})
+	}
+
+	return map[string]interface{}{
+		"Title":         "Blog · " + strings.Join(segments, " · "),
+		"Description":   i18n.T(language, "category.description", segments[len(segments)-1]),
+		"Category":      segments[len(segments)-1],
+		"Articles":      snapshot.ByCategory(category),
+		"Subcategories": subcategories,
+		"Breadcrumbs":   categoryBreadcrumbs(language, segments),
+		"FeedURL":       i18n.Localize(language, models.CategoryURL(segments)+"/feed.xml"),
+		"Alternates": alternates(models.CategoryURL(segments), func(snapshot *articles.Snapshot) bool {
+			return snapshot.HasCategory(category)
+		}),
+	}
+}
+
+func articlePage(snapshot *articles.Snapshot, article models.Article) map[string]interface{} {
+	var translations []i18n.Alternate
+	for _, translation := range snapshot.Translations(article) {
+		translations = append(translations, i18n.Alternate{Language: translation.Language, URL: translation.URL()})
+	}
+
+	unpublished := !article.Data.Published(time.Now())
+
+	// The front matter overrides the metadata derived from the article
+	description := article.Data.Description
+	if description == "" {
+		description = article.Excerpt
+	}
+
+	canonical := article.Data.Canonical
+	if canonical == "" {
+		canonical = article.URL()
+	}
+
+	robots := article.Data.Robots
+	if unpublished {
+		robots = "noindex, nofollow"
+	}
+
+	openGraph := models.OpenGraph{Type: article.Data.OpenGraphType}
+	if openGraph.Type == "" {
+		openGraph.Type = "article"
+	}
+
+	var images []string
+
+	// Cards are only made for published articles
+	if !unpublished {
+		openGraph.Image = article.OpenGraphImageURL()
+		openGraph.ImageWidth = image.CARD_WIDTH
+		openGraph.ImageHeight = image.CARD_HEIGHT
+		openGraph.ImageAlt = article.Data.Title
+
+		images = append(images, openGraph.Image)
+	}
+
+	if article.Data.Thumbnail != "" {
+		images = append(images, article.Data.Thumbnail)
+	}
+
+	return map[string]interface{}{
+		"Title":       article.Data.Title,
+		"Description": description,
+		"Canonical":   canonical,
+		"Robots":      robots,
+		"Article":     article,
+		"Unpublished": unpublished,
+		"OpenGraph":   openGraph,
+		"StructuredData": []interface{}{
+			jsonld.NewBlogPosting(config.Get(), article, description, canonical, images),
+		},
+		"TOC":         article.TOC,
+		"Related":     snapshot.Related(article.Category, article.Slug),
+		"Series":      snapshot.SeriesNavigation(article),
+		"Breadcrumbs": categoryBreadcrumbs(article.Language, article.Categories),
+		"History":     snapshot.HasHistory(article),
+		"Alternates":  translations,
+	}
+}
+
+func historyPage(c *gin.Context, snapshot *articles.Snapshot, article models.Article) map[string]interface{} {
+	// The article is what search engines should show
+	c.Header("X-Robots-Tag", "noindex")
+
+	var translations []i18n.Alternate
+	for _, translation := range snapshot.Translations(article) {
+		if snapshot.HasHistory(translation) {
+			translations = append(translations, i18n.Alternate{Language: translation.Language, URL: translation.HistoryURL()})
+		}
+	}
+
+	breadcrumbs := append(categoryBreadcrumbs(article.Language, article.Categories), models.Breadcrumb{
+		Name: article.Data.Title,
+		URL:  article.URL(),
+	})
+
+	return map[string]interface{}{
+		"Title":       i18n.T(article.Language, "history.title", article.Data.Title),
+		"Description": i18n.T(article.Language, "history.description", article.Data.Title),
+		"Robots":      "noindex",
+		"Article":     article,
+		"Revisions":   snapshot.History(article),
+		"Breadcrumbs": breadcrumbs,
+		"Alternates":  translations,
+	}
+}
+
+// categoryBreadcrumbs links the blog index and every level of a category
+// chain in language.
+func categoryBreadcrumbs(language string, categories []string) []models.Breadcrumb {
+	breadcrumbs := []models.Breadcrumb{{Name: "Blog", URL: i18n.Localize(language, "/blog")}}
+
+	for level, category := range categories {
+		breadcrumbs = append(breadcrumbs, models.Breadcrumb{
+			Name: category,
+			URL:  i18n.Localize(language, models.CategoryURL(categories[:level+1])),
+		})
+	}
+
+	return breadcrumbs
+}
@@ -1,11 +1,29 @@
 package controllers
 
 import (
-	"embed"
-
+	"coding-kittens.com/middlewares"
+	"coding-kittens.com/modules/articles"
+	"coding-kittens.com/modules/i18n"
 	"github.com/gin-gonic/gin"
 )
 
 type ControllerFunc func(c *gin.Context) map[string]interface{}
 
-var ArticlesFS embed.FS
\ No newline at end of file
+// requestLanguage returns the language of the requested page.
+func requestLanguage(c *gin.Context) string {
+	return c.MustGet("ContextData").(middlewares.ContextData).Language
+}
+
+// alternates returns the versions of the page at path, given without its
+// language prefix, in the languages whose articles exists is true for.
+func alternates(path string, exists func(snapshot *articles.Snapshot) bool) []i18n.Alternate {
+	var alternates []i18n.Alternate
+
+	for _, alternate := range i18n.Alternates(path) {
+		if exists(articles.Current().Language(alternate.Language)) {
+			alternates = append(alternates, alternate)
+		}
+	}
+
+	return alternates
+}
@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"coding-kittens.com/modules/articles"
+	"coding-kittens.com/modules/config"
+	"coding-kittens.com/modules/feed"
+	"coding-kittens.com/modules/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// FEED_ITEMS is how many of the latest articles a feed lists.
+const FEED_ITEMS = 20
+
+// FeedController serves the feed of every article of the requested language
+// in the given format.
+func FeedController(format feed.Format) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		site := config.Get()
+		language := requestLanguage(c)
+
+		writeFeed(c, format, feed.New(language, site.SiteName, site.SiteDescription, i18n.Localize(language, "/blog"), articles.Current().Language(language).All()))
+	}
+}
+
+// categoryFeed serves the RSS feed of a category and its subcategories.
+func categoryFeed(c *gin.Context, categories []string) {
+	category := strings.Join(categories, "/")
+	language := requestLanguage(c)
+	snapshot := articles.Current().Language(language)
+
+	if !snapshot.HasCategory(category) {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+
+	site := config.Get()
+	title := site.SiteName + " · " + strings.Join(categories, " · ")
+
+	description := i18n.T(language, "category.description", categories[len(categories)-1])
+
+	writeFeed(c, feed.RSS, feed.New(language, title, description, i18n.Localize(language, "/blog/"+category), snapshot.ByCategory(category)))
+	c.Abort()
+}
+
+func writeFeed(c *gin.Context, format feed.Format, f feed.Feed) {
+	if len(f.Items) > FEED_ITEMS {
+		f.Items = f.Items[:FEED_ITEMS]
+	}
+
+	body, err := format.Encode(f, config.Get().AbsoluteURL(c.Request.URL.Path))
+	if err != nil {
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	if !f.Updated.IsZero() {
+		c.Header("Last-Modified", f.Updated.UTC().Format(http.TimeFormat))
+	}
+
+	c.Data(http.StatusOK, format.ContentType, body)
+}
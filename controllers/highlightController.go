@@ -0,0 +1,14 @@
+package controllers
+
+import (
+	"net/http"
+
+	"coding-kittens.com/modules/markdown"
+	"github.com/gin-gonic/gin"
+)
+
+// HighlightStylesController serves the colours of the highlighted code blocks.
+func HighlightStylesController(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Data(http.StatusOK, "text/css; charset=utf-8", markdown.HighlightCSS())
+}
@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"coding-kittens.com/modules/articles"
+	"coding-kittens.com/modules/config"
+	"coding-kittens.com/modules/image"
+	"coding-kittens.com/modules/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// OpenGraphImageController serves the social preview card of an article,
+// e.g. /og/css/slug.png.
+func OpenGraphImageController(c *gin.Context) {
+	articlePath, ok := strings.CutSuffix(strings.Trim(c.Param("path"), "/"), ".png")
+	separator := strings.LastIndex(articlePath, "/")
+
+	if !ok || separator < 0 {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+
+	article, ok := articles.Current().Language(requestLanguage(c)).Get(articlePath[:separator], articlePath[separator+1:])
+	if !ok {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+
+	card, err := image.OpenGraphCard(image.Card{
+		Thumbnail: article.Data.Thumbnail,
+		Title:     article.Data.Title,
+		Subtitle:  article.Data.Subtitle,
+		SiteName:  config.Get().SiteName,
+		Accent:    utils.GetAccentBaseValue(),
+	})
+	if err != nil {
+		log.Printf("Error rendering the card of %s: %v", article.URL(), err)
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Data(http.StatusOK, "image/png", card)
+}
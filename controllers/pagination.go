@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"net/url"
+	"strconv"
+
+	"coding-kittens.com/models"
+)
+
+const ARTICLES_PER_PAGE = 10
+
+// Pagination describes the current page of a listing and links to its
+// neighbours. The links keep the rest of the query string untouched.
+type Pagination struct {
+	Page       int
+	TotalPages int
+	URL        string // link to the current page, its canonical URL
+	PrevURL    string
+	NextURL    string
+}
+
+// parsePage reads a 1-based page number, defaulting to the first page.
+func parsePage(value string) int {
+	page, err := strconv.Atoi(value)
+	if err != nil || page < 1 {
+		return 1
+	}
+
+	return page
+}
+
+// paginate slices the articles of the requested page and reports false when
+// the page is out of range.
+func paginate(articles []models.Article, page int, path string, query url.Values) ([]models.Article, Pagination, bool) {
+	totalPages := (len(articles) + ARTICLES_PER_PAGE - 1) / ARTICLES_PER_PAGE
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	if page > totalPages {
+		return nil, Pagination{}, false
+	}
+
+	pageURL := func(page int) string {
+		values := url.Values{}
+		for key, value := range query {
+			values[key] = value
+		}
+
+		if page > 1 {
+			values.Set("page", strconv.Itoa(page))
+		} else {
+			values.Del("page")
+		}
+
+		if encoded := values.Encode(); encoded != "" {
+			return path + "?" + encoded
+		}
+
+		return path
+	}
+
+	pagination := Pagination{Page: page, TotalPages: totalPages, URL: pageURL(page)}
+
+	if page > 1 {
+		pagination.PrevURL = pageURL(page - 1)
+	}
+
+	if page < totalPages {
+		pagination.NextURL = pageURL(page + 1)
+	}
+
+	start := (page - 1) * ARTICLES_PER_PAGE
+	end := start + ARTICLES_PER_PAGE
+	if end > len(articles) {
+		end = len(articles)
+	}
+
+	return articles[start:end], pagination, true
+}
@@ -0,0 +1,27 @@
+package controllers
+
+import (
+	"strings"
+
+	"coding-kittens.com/modules/articles"
+	"coding-kittens.com/modules/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+const SEARCH_RESULTS = 20
+
+func SearchController(c *gin.Context) map[string]interface{} {
+	query := strings.TrimSpace(c.Query("q"))
+	language := requestLanguage(c)
+
+	data := map[string]interface{}{
+		"Query":   query,
+		"Results": articles.Current().Language(language).Search(query, SEARCH_RESULTS),
+	}
+
+	if query != "" {
+		data["Title"] = i18n.T(language, "search.title", query)
+	}
+
+	return data
+}
@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"net/http"
+
+	"coding-kittens.com/models"
+	"coding-kittens.com/modules/articles"
+	"coding-kittens.com/modules/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+func SeriesController(c *gin.Context) map[string]interface{} {
+	slug := articles.SeriesSlug(c.Param("name"))
+	language := requestLanguage(c)
+
+	// Send differently spelled names to the canonical page
+	if slug != c.Param("name") {
+		c.Redirect(http.StatusMovedPermanently, i18n.Localize(language, models.SeriesURL(slug)))
+		c.Abort()
+		return nil
+	}
+
+	series, ok := articles.Current().Language(language).Series(slug)
+
+	if !ok {
+		c.AbortWithStatus(http.StatusNotFound)
+		return nil
+	}
+
+	return map[string]interface{}{
+		"Title":       i18n.T(language, "series.title", series.Name),
+		"Description": i18n.T(language, "series.description", series.Name, len(series.Articles)),
+		"Alternates": alternates(models.SeriesURL(slug), func(snapshot *articles.Snapshot) bool {
+			_, ok := snapshot.Series(slug)
+			return ok
+		}),
+		"Series": series,
+	}
+}
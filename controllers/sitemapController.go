@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"coding-kittens.com/models"
+	"coding-kittens.com/modules/articles"
+	"coding-kittens.com/modules/config"
+	"coding-kittens.com/modules/i18n"
+	"coding-kittens.com/modules/sitemap"
+	"github.com/gin-gonic/gin"
+)
+
+// latest returns the last modification date of a list of articles.
+func latest(list []models.Article) time.Time {
+	var lastModified time.Time
+
+	for _, article := range list {
+		if date := article.Data.LastModified(); date.After(lastModified) {
+			lastModified = date
+		}
+	}
+
+	return lastModified
+}
+
+// sitemapURLs lists, for every language with articles, the static pages at
+// paths, then every category, tag, series and article page.
+func sitemapURLs(paths []string) []sitemap.URL {
+	site := config.Get()
+
+	var urls []sitemap.URL
+
+	sorted := append([]string(nil), paths...)
+	sort.Strings(sorted)
+
+	for _, language := range i18n.LANGUAGES {
+		snapshot := articles.Current().Language(language)
+		all := snapshot.All()
+
+		// The pages of a language nothing is translated to only repeat the
+		// default language
+		if len(all) == 0 && language != i18n.DEFAULT_LANGUAGE {
+			continue
+		}
+
+		localize := func(path string) string {
+			return site.AbsoluteURL(i18n.Localize(language, path))
+		}
+
+		for _, path := range sorted {
+			if disallowed(site, path) {
+				continue
+			}
+
+			urls = append(urls, sitemap.Entry(localize(path), latest(all)))
+		}
+
+		for _, category := range snapshot.Categories() {
+			urls = append(urls, sitemap.Entry(localize(models.CategoryURL(strings.Split(category, "/"))), latest(snapshot.ByCategory(category))))
+		}
+
+		for _, tag := range snapshot.Tags() {
+			urls = append(urls, sitemap.Entry(localize(models.TagURL(tag.Name)), latest(snapshot.ByTag(tag.Name))))
+		}
+
+		for _, series := range snapshot.AllSeries() {
+			urls = append(urls, sitemap.Entry(site.AbsoluteURL(series.URL()), latest(series.Articles)))
+		}
+
+		for _, article := range all {
+			urls = append(urls, sitemap.Entry(site.AbsoluteURL(article.URL()), article.Data.LastModified()))
+		}
+	}
+
+	return urls
+}
+
+// disallowed reports whether robots.txt keeps crawlers out of path.
+func disallowed(site config.Config, path string) bool {
+	for _, prefix := range site.RobotsDisallow {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func writeSitemap(c *gin.Context, body []byte, err error) {
+	if err != nil {
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", body)
+}
+
+// SitemapController serves /sitemap.xml, which turns into a sitemap index of
+// /sitemaps/<n>.xml once the site outgrows a single sitemap.
+func SitemapController(paths []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		urls := sitemapURLs(paths)
+		pages := sitemap.Paginate(urls)
+
+		if len(pages) == 1 {
+			body, err := sitemap.URLSet(pages[0])
+			writeSitemap(c, body, err)
+			return
+		}
+
+		lastModified := latest(articles.Current().All())
+
+		var sitemaps []sitemap.URL
+		for i := range pages {
+			sitemaps = append(sitemaps, sitemap.Entry(config.Get().AbsoluteURL(fmt.Sprintf("/sitemaps/%d.xml", i+1)), lastModified))
+		}
+
+		body, err := sitemap.Index(sitemaps)
+		writeSitemap(c, body, err)
+	}
+}
+
+// SitemapPageController serves the sitemaps listed by the sitemap index.
+func SitemapPageController(paths []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		page, err := strconv.Atoi(strings.TrimSuffix(c.Param("page"), ".xml"))
+		pages := sitemap.Paginate(sitemapURLs(paths))
+
+		if err != nil || !strings.HasSuffix(c.Param("page"), ".xml") || page < 1 || page > len(pages) {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+
+		body, err := sitemap.URLSet(pages[page-1])
+		writeSitemap(c, body, err)
+	}
+}
+
+// RobotsController serves /robots.txt. Outside production every crawler is
+// kept out so staging and local copies don't end up indexed.
+func RobotsController(c *gin.Context) {
+	site := config.Get()
+
+	var sb strings.Builder
+
+	sb.WriteString("User-agent: *\n")
+
+	if site.Production() {
+		if len(site.RobotsDisallow) == 0 {
+			sb.WriteString("Disallow:\n")
+		}
+
+		for _, path := range site.RobotsDisallow {
+			for _, language := range i18n.LANGUAGES {
+				sb.WriteString("Disallow: " + i18n.Localize(language, path) + "\n")
+			}
+		}
+	} else {
+		sb.WriteString("Disallow: /\n")
+	}
+
+	sb.WriteString("\nSitemap: " + site.AbsoluteURL("/sitemap.xml") + "\n")
+
+	c.String(http.StatusOK, sb.String())
+}
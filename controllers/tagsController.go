@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"net/http"
+	"net/url"
+
+	"coding-kittens.com/models"
+	"coding-kittens.com/modules/articles"
+	"coding-kittens.com/modules/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+func TagsController(c *gin.Context) map[string]interface{} {
+	return map[string]interface{}{
+		"Tags": articles.Current().Language(requestLanguage(c)).Tags(),
+	}
+}
+
+func TagController(c *gin.Context) map[string]interface{} {
+	param := c.Param("tag")
+	tag := articles.NormalizeTag(param)
+	language := requestLanguage(c)
+
+	// Send aliases and differently spelled tags to the canonical page
+	if tag != param {
+		c.Redirect(http.StatusMovedPermanently, i18n.Localize(language, models.TagURL(tag)))
+		c.Abort()
+		return nil
+	}
+
+	tagArticles := articles.Current().Language(language).ByTag(tag)
+
+	if len(tagArticles) == 0 {
+		c.AbortWithStatus(http.StatusNotFound)
+		return nil
+	}
+
+	pageArticles, pagination, ok := paginate(tagArticles, parsePage(c.Query("page")), i18n.Localize(language, models.TagURL(tag)), url.Values{})
+
+	if !ok {
+		c.AbortWithStatus(http.StatusNotFound)
+		return nil
+	}
+
+	return map[string]interface{}{
+		"Title":       i18n.T(language, "tag.title", tag),
+		"Description": i18n.T(language, "category.description", tag),
+		"Alternates": alternates(models.TagURL(tag), func(snapshot *articles.Snapshot) bool {
+			return len(snapshot.ByTag(tag)) > 0
+		}),
+		"Tag":        tag,
+		"Articles":   pageArticles,
+		"Pagination": pagination,
+		"Canonical":  pagination.URL,
+	}
+}
@@ -4,19 +4,35 @@ import (
 	"bytes"
 	"context"
 	"embed"
+	"errors"
 	"flag"
+	"fmt"
 	"html/template"
+	"io"
 	"io/fs"
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"coding-kittens.com/controllers"
 	"coding-kittens.com/middlewares"
+	"coding-kittens.com/models"
 	"coding-kittens.com/modules/articles"
+	"coding-kittens.com/modules/check"
+	"coding-kittens.com/modules/config"
+	"coding-kittens.com/modules/content"
+	"coding-kittens.com/modules/export"
+	"coding-kittens.com/modules/feed"
+	"coding-kittens.com/modules/i18n"
 	"coding-kittens.com/modules/image"
+	"coding-kittens.com/modules/jsonld"
 	"coding-kittens.com/modules/livereload"
+	"coding-kittens.com/modules/markdown"
+	"coding-kittens.com/modules/preview"
 	"coding-kittens.com/modules/utils"
 	"coding-kittens.com/routes"
 	ginCompressor "github.com/CAFxX/httpcompression/contrib/gin-gonic/gin"
@@ -24,24 +40,27 @@ import (
 	"github.com/gin-gonic/gin/render"
 )
 
-//go:embed web/templates/*.tmpl
-var templateFiles embed.FS
-//go:embed all:web/static
-var staticAssets embed.FS
-//go:embed all:web/_articles
-var articlesFS embed.FS
+//go:embed web/templates/*.tmpl all:web/static all:web/_articles all:web/_examples web/locales/*.json
+var webFiles embed.FS
+
+// templates holds the parsed templates of every language, swapped along
+// with the content they're read from.
+var templates atomic.Pointer[map[string]*template.Template]
 
 func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	utils.StaticAssets = staticAssets
-	image.StaticAssets = staticAssets
-	controllers.ArticlesFS = articlesFS
-	articles.ArticlesFS = articlesFS
+	utils.StaticAssets = content.Static
+	image.StaticAssets = content.Static
+	markdown.StaticAssets = content.Static
+	feed.StaticAssets = content.Static
+	markdown.Templates = content.Templates
+	markdown.Examples = content.Examples
 
 	useHTTPS := flag.Bool("https", false, "start HTTPS server")
 	debug := flag.Bool("debug", false, "Enable debug mode")
+	contentDir := flag.String("content", "", "Serve the articles, templates and static files from a directory, reloading them when they change")
 
     flag.Parse()
 
@@ -51,10 +70,76 @@ func main() {
         gin.SetMode(gin.ReleaseMode)
     }
 
+	environment := config.PRODUCTION
+	if gin.IsDebugging() {
+		environment = "development"
+	}
+
+	if *useHTTPS {
+		config.Load("https://coding-kittens.dev.com", environment)
+	} else {
+		config.Load("http://localhost:8080", environment)
+	}
+
+	// The content is read from the -content flag or CONTENT_DIR, or from the
+	// working copy while developing, and the compiled-in copy otherwise
+	dir := *contentDir
+	if dir == "" {
+		dir = config.Get().ContentDir
+	}
+
+	if dir == "" && gin.IsDebugging() {
+		dir = "web"
+	}
+
+	// Drafts and scheduled articles are listed while writing them
+	repository := articles.Load(content.Articles, articles.Options{ShowUnpublished: gin.IsDebugging()})
+
+	// The server keeps its last good copy when articles of the directory
+	// fail to load, check and export report them instead
+	strict := flag.Arg(0) != "check" && flag.Arg(0) != "export"
+
+	webRoot, _ := fs.Sub(webFiles, "web")
+
+	source := content.NewSource(dir, content.Embedded(webRoot), func(c content.Content) (func(), error) {
+		return prepareContent(c, repository, strict)
+	})
+
+	// check reports what the loading of the content logs as problems
+	if flag.Arg(0) == "check" {
+		log.SetOutput(io.Discard)
+	}
+
+	reloadErr := source.Reload()
+
+	// go run main.go preview <category>/<file> prints a signed link to a
+	// draft or scheduled article
+	if flag.Arg(0) == "preview" {
+		printPreviewURL(flag.Arg(1))
+		return
+	}
+
+	// go run main.go check validates every article and exits non-zero when
+	// any of them is broken
+	if flag.Arg(0) == "check" {
+		os.Exit(checkContent(reloadErr))
+	}
+
+	if templates.Load() == nil {
+		log.Fatal("Failed to load the templates")
+	}
+
+	// go run main.go export [dir] writes the whole site as static files
+	if flag.Arg(0) == "export" {
+		os.Exit(exportSite(flag.Arg(1)))
+	}
+
 	if gin.IsDebugging() {
 		go livereload.StartLiveReload(ctx)
 	}
 
+	go source.Watch(ctx)
+
 	r := setupRouter()
 
 	var addr string
@@ -86,6 +171,108 @@ func main() {
 	select {}
 }
 
+func printPreviewURL(article string) {
+	// Translations are given with their language prefix, e.g. es/css/slug
+	language, article := i18n.Split("/" + strings.Trim(article, "/"))
+	article = strings.Trim(strings.TrimPrefix(strings.Trim(article, "/"), "blog/"), "/")
+
+	if !strings.Contains(article, "/") {
+		log.Fatal("Usage: preview [<language>/]<category>/<file>")
+	}
+
+	// Previews are checked against the URL of the article, which follows
+	// the slug of its front matter
+	found, ok := articles.Current().Language(language).File(article)
+	if !ok {
+		log.Fatalf("No article at %s", article)
+	}
+
+	url := preview.URL(found.URL(), preview.DEFAULT_TTL)
+	if url == "" {
+		log.Fatal("PREVIEW_SECRET is not set")
+	}
+
+	fmt.Println(url)
+}
+
+// EXPORT_DIR is where the export command writes the site by default.
+const EXPORT_DIR = "dist"
+
+// EXPORT_EXCLUDED are the pages the export leaves out, as they answer queries
+// static hosting can't.
+var EXPORT_EXCLUDED = []string{"/search"}
+
+func exportSite(out string) int {
+	if out == "" {
+		out = EXPORT_DIR
+	}
+
+	// A site without the articles that failed to load isn't worth publishing
+	if errs := articles.Current().Errors(); len(errs) > 0 {
+		log.Printf("Failed to export the site: %d articles failed to load", len(errs))
+		return 1
+	}
+
+	// Thousands of requests are about to go through the router
+	gin.DefaultWriter = io.Discard
+
+	router := setupRouter()
+
+	// Every page without parameters in every language, the feeds and the
+	// sitemap, which leads to the articles and taxonomies
+	seeds := []string{"/sitemap.xml", "/robots.txt"}
+
+	for _, language := range i18n.LANGUAGES {
+		for route := range routes.GetRoutes() {
+			if !strings.ContainsAny(route, ":*") {
+				seeds = append(seeds, i18n.Localize(language, route))
+			}
+		}
+
+		for _, feedPath := range []string{"/feed.xml", "/atom.xml", "/feed.json"} {
+			seeds = append(seeds, i18n.Localize(language, feedPath))
+		}
+	}
+
+	pages, err := export.Site(router, content.Static, config.Get().SiteURL, seeds, EXPORT_EXCLUDED, out)
+	if err != nil {
+		log.Printf("Failed to export the site: %v", err)
+		return 1
+	}
+
+	log.Printf("Exported %d pages to %s", pages, out)
+
+	return 0
+}
+
+func checkContent(reloadErr error) int {
+	var routes []string
+	for _, route := range setupRouter().Routes() {
+		routes = append(routes, route.Path)
+	}
+
+	diagnostics := check.Content(content.Current().Dir, content.Articles, content.Static, routes)
+
+	// The embedded copy was checked instead of the directory
+	if reloadErr != nil {
+		message := fmt.Sprintf("the content directory can't be used, the embedded copy was checked instead: %v", reloadErr)
+		diagnostics = append([]check.Diagnostic{{Message: message}}, diagnostics...)
+	}
+
+	for _, diagnostic := range diagnostics {
+		fmt.Fprintln(os.Stderr, diagnostic)
+	}
+
+	if len(diagnostics) > 0 {
+		fmt.Fprintf(os.Stderr, "%d problems found\n", len(diagnostics))
+		return 1
+	}
+
+	fmt.Println("No problems found")
+
+	return 0
+}
+
 func setupRouter() *gin.Engine {
 	router := gin.Default()
 	
@@ -100,13 +287,36 @@ func setupRouter() *gin.Engine {
 
 	loadTemplates(router)
 
+	var sitemapPaths []string
+
 	for route, data := range routes.GetRoutes() {
-		router.GET(route, handleRoute(data, router))
+		// Every page is served in every language, e.g. /blog and /es/blog
+		for _, language := range i18n.LANGUAGES {
+			router.GET(i18n.Localize(language, route), handleRoute(data, router))
+		}
+
+		// Routes with parameters are listed by the sitemap through the
+		// articles and taxonomies instead
+		if !strings.ContainsAny(route, ":*") {
+			sitemapPaths = append(sitemapPaths, route)
+		}
 	}
 
 	router.GET("/image", image.ProcessImage)
+	router.GET("/highlight.css", controllers.HighlightStylesController)
 
-	router.StaticFS("/static", http.FS(loadFS(staticAssets, "web/static")))
+	for _, language := range i18n.LANGUAGES {
+		router.GET(i18n.Localize(language, "/feed.xml"), controllers.FeedController(feed.RSS))
+		router.GET(i18n.Localize(language, "/atom.xml"), controllers.FeedController(feed.Atom))
+		router.GET(i18n.Localize(language, "/feed.json"), controllers.FeedController(feed.JSON))
+		router.GET(i18n.Localize(language, "/og/*path"), controllers.OpenGraphImageController)
+	}
+
+	router.GET("/sitemap.xml", controllers.SitemapController(sitemapPaths))
+	router.GET("/sitemaps/:page", controllers.SitemapPageController(sitemapPaths))
+	router.GET("/robots.txt", controllers.RobotsController)
+
+	router.StaticFS("/static", http.FS(content.Static))
 	router.StaticFile("/favicon.ico", "./web/favicon.ico")
 
 	return router
@@ -114,7 +324,7 @@ func setupRouter() *gin.Engine {
 
 
 func loadTemplates(router *gin.Engine) error {
-    templateFS, err := template.ParseFS(loadFS(templateFiles, "web/templates"), "*.tmpl")
+    templateFS, err := template.New("").Funcs(templateFuncs(i18n.DEFAULT_LANGUAGE)).ParseFS(content.Templates, "*.tmpl")
 
     if err != nil {
         return err
@@ -125,16 +335,78 @@ func loadTemplates(router *gin.Engine) error {
     return nil
 }
 
-func loadFS(embed embed.FS, dir string) fs.FS {
-	var fileSystem fs.FS
+// parseTemplates parses the templates of fsys once for every language, the
+// helpers being bound to it.
+func parseTemplates(fsys fs.FS) (map[string]*template.Template, error) {
+	parsed := map[string]*template.Template{}
 
-	if gin.IsDebugging() {
-		fileSystem = os.DirFS(dir)
+	for _, language := range i18n.LANGUAGES {
+		t, err := template.New("").Funcs(templateFuncs(language)).ParseFS(fsys, "*.tmpl")
+		if err != nil {
+			return nil, err
+		}
+
+		parsed[language] = t
+	}
+
+	// Every route has to be renderable
+	for route, data := range routes.GetRoutes() {
+		for _, name := range []string{"root.tmpl", data.Content, data.Partial} {
+			if name != "" && parsed[i18n.DEFAULT_LANGUAGE].Lookup(name) == nil {
+				return nil, fmt.Errorf("no template %q for %s", name, route)
+			}
+		}
+	}
+
+	return parsed, nil
+}
+
+// prepareContent loads the templates, message catalogues and articles of a
+// copy of the content, leaving the current ones in place when any of them is
+// invalid. Articles that fail to load only make the copy invalid when strict
+// and it was read from a directory, which keeps the last good copy served,
+// or the embedded one until the directory has loaded. The function it
+// returns publishes them all at once.
+func prepareContent(c content.Content, repository *articles.Repository, strict bool) (func(), error) {
+	parsed, err := parseTemplates(c.Templates)
+	if err != nil {
+		return nil, fmt.Errorf("templates: %w", err)
+	}
+
+	catalogues, err := i18n.Parse(c.Locales)
+	if err != nil {
+		return nil, fmt.Errorf("locales: %w", err)
+	}
+
+	// Articles read from a directory are dated by its git history
+	if c.Dir != "" {
+		articles.UseHistory(filepath.Join(c.Dir, content.ARTICLES_DIR))
 	} else {
-		fileSystem, _ = fs.Sub(embed, dir)
+		articles.UseHistory("")
 	}
 
-	return fileSystem
+	files := articles.Files{Articles: c.Articles, Templates: c.Templates, Static: c.Static, Examples: c.Examples}
+	if c.Dir != "" {
+		files.Dir = filepath.Join(c.Dir, content.ARTICLES_DIR)
+	}
+
+	snapshot := repository.Build(files)
+
+	if errs := snapshot.Errors(); strict && c.Dir != "" && len(errs) > 0 {
+		return nil, fmt.Errorf("articles: %w", errors.Join(errs...))
+	}
+
+	reloaded := templates.Load() != nil
+
+	return func() {
+		templates.Store(&parsed)
+		i18n.Store(catalogues)
+		repository.Publish(snapshot)
+
+		if reloaded {
+			log.Printf("Articles reloaded: %d articles", len(snapshot.All()))
+		}
+	}, nil
 }
 
 func handleRoute(data routes.RouteData, router *gin.Engine) gin.HandlerFunc {
@@ -145,8 +417,56 @@ func handleRoute(data routes.RouteData, router *gin.Engine) gin.HandlerFunc {
 	}
 }
 
+// templateFuncs are the helpers available to every template, bound to the
+// language of the page.
+func templateFuncs(language string) template.FuncMap {
+	return template.FuncMap{
+		"tagCloud": func() []articles.TagCount {
+			return articles.TagCloud(language)
+		},
+		"tagURL": func(tag string) string {
+			return i18n.Localize(language, models.TagURL(tag))
+		},
+		"categoryURL": func(category string) string {
+			return i18n.Localize(language, models.CategoryURL(strings.Split(category, "/")))
+		},
+		"localize": func(path string) string {
+			return i18n.Localize(language, path)
+		},
+		"t": func(key string, args ...interface{}) string {
+			return i18n.T(language, key, args...)
+		},
+		"date": func(format string, date time.Time) string {
+			return i18n.Date(language, format, date)
+		},
+		"languageName": func(language string) string {
+			return i18n.T(language, "language.name")
+		},
+		"add": func(a int, b int) int {
+			return a + b
+		},
+	}
+}
+
+// DEFAULT_OPEN_GRAPH_IMAGE previews the pages without a card of their own.
+const DEFAULT_OPEN_GRAPH_IMAGE = "/static/assets/logo.png"
+
+// absoluteAlternates turns the links of the alternates into absolute URLs,
+// as hreflang links require.
+func absoluteAlternates(alternates []i18n.Alternate) []i18n.Alternate {
+	absolute := make([]i18n.Alternate, 0, len(alternates))
+
+	for _, alternate := range alternates {
+		absolute = append(absolute, i18n.Alternate{Language: alternate.Language, URL: config.Get().AbsoluteURL(alternate.URL)})
+	}
+
+	return absolute
+}
+
 func renderTemplate(c *gin.Context, data routes.RouteData, ctxData middlewares.ContextData) {
-	t := template.Must(template.New(data.Content).ParseFS(loadFS(templateFiles, "web/templates"), "*.tmpl"))
+	language := ctxData.Language
+
+	t := (*templates.Load())[language]
 
 	var contentBuffer bytes.Buffer
 	var templateData map[string]interface{}
@@ -157,6 +477,58 @@ func renderTemplate(c *gin.Context, data routes.RouteData, ctxData middlewares.C
 		templateData = make(map[string]interface{})
 	}
 
+	if c.IsAborted() {
+		return
+	}
+
+	_, path := i18n.Split(c.Request.URL.Path)
+
+	// Controllers list the languages their page exists in, every other page
+	// exists in the languages there are articles in
+	alternates, ok := templateData["Alternates"].([]i18n.Alternate)
+	if !ok {
+		for _, alternate := range i18n.Alternates(path) {
+			if len(articles.Current().Language(alternate.Language).All()) > 0 {
+				alternates = append(alternates, alternate)
+			}
+		}
+	}
+
+	// Pages of the default language have no prefix, so visitors who prefer
+	// another one are sent to their translation when there is one
+	c.Header("Vary", "Accept-Language, Cookie")
+
+	if language == i18n.DEFAULT_LANGUAGE && ctxData.PreferredLanguage != language && c.GetHeader("HX-Request") != "true" {
+		for _, alternate := range alternates {
+			if alternate.Language == ctxData.PreferredLanguage {
+				location := alternate.URL
+				if c.Request.URL.RawQuery != "" {
+					location += "?" + c.Request.URL.RawQuery
+				}
+
+				c.Redirect(http.StatusFound, location)
+				return
+			}
+		}
+	}
+
+	// htmx requests that aren't boosted navigations, e.g. infinite scroll,
+	// only need the fragment and not the whole page
+	if data.Partial != "" && c.GetHeader("HX-Request") == "true" && c.GetHeader("HX-Boosted") != "true" {
+		var partialBuffer bytes.Buffer
+
+		if err := t.ExecuteTemplate(&partialBuffer, data.Partial, templateData); err != nil {
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.Render(http.StatusOK, render.Data{
+			ContentType: "text/html",
+			Data:        partialBuffer.Bytes(),
+		})
+		return
+	}
+
 	err := t.ExecuteTemplate(&contentBuffer, data.Content, templateData)
 
 	if err != nil {
@@ -178,22 +550,98 @@ func renderTemplate(c *gin.Context, data routes.RouteData, ctxData middlewares.C
 
 	var rootContentBuffer bytes.Buffer
 
-	template.Must(template.New("root.tmpl").ParseFS(loadFS(templateFiles, "web/templates"), "root.tmpl"))
+	// Controllers can override the route metadata, e.g. with the article title
+	title := data.Title
+	if translated, ok := i18n.Lookup(language, "title."+data.Content); ok {
+		title = translated
+	}
+
+	if value, ok := templateData["Title"].(string); ok && value != "" {
+		title = value
+	}
+
+	description := data.Description
+	if translated, ok := i18n.Lookup(language, "description."+data.Content); ok {
+		description = translated
+	}
+
+	if value, ok := templateData["Description"].(string); ok && value != "" {
+		description = value
+	}
+
+	// Pages are their own canonical URL, without the query, unless the route
+	// or the controller point at another one
+	canonical := path
+	if data.Canonical != "" {
+		canonical = data.Canonical
+	}
+
+	canonical = i18n.Localize(language, canonical)
+
+	if value, ok := templateData["Canonical"].(string); ok && value != "" {
+		canonical = value
+	}
+
+	canonical = config.Get().AbsoluteURL(canonical)
+
+	robots := data.Robots
+	if value, ok := templateData["Robots"].(string); ok && value != "" {
+		robots = value
+	}
+
+	// Pages without a card of their own are previewed with the logo
+	openGraph, ok := templateData["OpenGraph"].(models.OpenGraph)
+	if !ok {
+		openGraph = models.OpenGraph{Type: data.OpenGraphType}
+	}
+
+	if openGraph.Type == "" {
+		openGraph.Type = "website"
+	}
+
+	if openGraph.Image == "" {
+		openGraph.Image = DEFAULT_OPEN_GRAPH_IMAGE
+		openGraph.ImageWidth, openGraph.ImageHeight = 0, 0
+		openGraph.ImageAlt = config.Get().SiteName
+	}
+
+	openGraph.Image = config.Get().AbsoluteURL(openGraph.Image)
+
+	structuredData, _ := templateData["StructuredData"].([]interface{})
+
+	if breadcrumbs, ok := templateData["Breadcrumbs"].([]models.Breadcrumb); ok {
+		page := models.Breadcrumb{Name: title, URL: c.Request.URL.Path}
+		structuredData = append(structuredData, jsonld.NewBreadcrumbList(config.Get(), breadcrumbs, page))
+	}
 
 	renderData := struct {
         LiveReloadEnabled bool
         Title             string
         Description       string
+        Canonical         string
+        Robots            string
+        StructuredData    []interface{}
         Route             string
         Template          template.HTML
         AccentHue         float64
+        Language          string
+        Alternates        []i18n.Alternate
+        SiteName          string
+        OpenGraph         models.OpenGraph
     }{
         LiveReloadEnabled: ctxData.LiveReloadEnabled,
-        Title:             data.Title,
-        Description:       "change to some metadata description, can be overriden on route basis",
+        Title:             title,
+        Description:       description,
+        Canonical:         canonical,
+        Robots:            robots,
+        StructuredData:    structuredData,
         Route:             c.Request.URL.Path,
         Template:          template.HTML(contentBuffer.String()),
         AccentHue:         ctxData.AccentBaseHSL.H,
+        Language:          language,
+        Alternates:        absoluteAlternates(alternates),
+        SiteName:          config.Get().SiteName,
+        OpenGraph:         openGraph,
     }
 
 	err = t.ExecuteTemplate(&rootContentBuffer, "root.tmpl", renderData)
@@ -4,6 +4,7 @@ import (
 	"sync"
 
 	"coding-kittens.com/modules/color"
+	"coding-kittens.com/modules/i18n"
 	"coding-kittens.com/modules/utils"
 	"github.com/gin-gonic/gin"
 )
@@ -12,9 +13,15 @@ import (
 type ContextData struct {
 	LiveReloadEnabled bool
 	AccentBaseHSL     color.HSL
+	Language          string // language of the page, from its path prefix
+	PreferredLanguage string // language negotiated with the visitor
 	// Add more fields as needed
 }
 
+// LANGUAGE_COOKIE_MAX_AGE is how long the picked language is remembered, in
+// seconds.
+const LANGUAGE_COOKIE_MAX_AGE = 365 * 24 * 60 * 60
+
 var accentBaseOnce sync.Once
 var accentBaseHSL color.HSL
 
@@ -26,10 +33,24 @@ func SetContextDataMiddleware() gin.HandlerFunc {
 			accentBaseHSL, _ = color.HextoHSL(utils.GetAccentBaseValue())
 		})
 
+		language, _ := i18n.Split(c.Request.URL.Path)
+
+		// Links of the language switch remember the picked language
+		preferred := c.Query(i18n.QUERY_PARAMETER)
+
+		if i18n.Supported(preferred) {
+			c.SetCookie(i18n.COOKIE_NAME, preferred, LANGUAGE_COOKIE_MAX_AGE, "/", "", false, true)
+		} else {
+			cookie, _ := c.Cookie(i18n.COOKIE_NAME)
+			preferred = i18n.Negotiate(cookie, c.GetHeader("Accept-Language"))
+		}
+
 		// Create a custom context data struct
 		contextData := ContextData{
 			LiveReloadEnabled: gin.IsDebugging(),
 			AccentBaseHSL:     accentBaseHSL,
+			Language:          language,
+			PreferredLanguage: preferred,
 		}
 
 		// Set the custom context data
@@ -1,12 +1,171 @@
 package models
 
+import (
+	"html/template"
+	"net/url"
+	"strings"
+	"time"
+
+	"coding-kittens.com/modules/i18n"
+)
+
 type Article struct {
 	Slug      string
-	Category  string
+	Language  string
+	TranslationKey string // category and slug of the original, shared by its translations
+	Source    string // path of the article file, e.g. web/_articles/css/slug.mdx
+	Category  string // full category chain joined with "/", e.g. "frontend/css"
+	Categories []string
 	Data FrontMatter
+	Content   template.HTML
+	Text      string // plain text of the body, without code blocks
+	Headings  []string
+	TOC       []TOCEntry
+	Excerpt   string // shortDescription, or else the start of the first paragraph
+	WordCount int
+	ReadingTime int // minutes
+}
+
+// URL returns the path of the article page.
+func (a Article) URL() string {
+	return i18n.Localize(a.Language, "/blog/"+a.Category+"/"+a.Slug)
+}
+
+// HistoryURL returns the path of the page listing the revisions of the
+// article.
+func (a Article) HistoryURL() string {
+	return a.URL() + "/history"
+}
+
+// OpenGraphImageURL returns the path of the social preview card of the
+// article.
+func (a Article) OpenGraphImageURL() string {
+	return i18n.Localize(a.Language, "/og/"+a.Category+"/"+a.Slug+".png")
+}
+
+// CategoryURL returns the path of the listing page of a category chain.
+func CategoryURL(categories []string) string {
+	return "/blog/" + strings.Join(categories, "/")
 }
 
 type FrontMatter struct {
+	Slug string // slug of a translation, e.g. in its own language
 	Thumbnail string
 	Title string
-}
\ No newline at end of file
+	Subtitle string
+	ShortDescription string `yaml:"shortDescription"`
+	CreatedAt time.Time `yaml:"createdAt"`
+	UpdatedAt time.Time `yaml:"updatedAt"`
+	Tags []string
+	Draft bool
+	PublishAt time.Time `yaml:"publishAt"`
+	Author string
+	Series string
+	SeriesOrder int `yaml:"seriesOrder"`
+	Description string // metadata description, the excerpt when empty
+	Canonical string // canonical URL when the article was first published elsewhere
+	Robots string // robots directives, e.g. "noindex"
+	OpenGraphType string `yaml:"ogType"` // "article" when empty
+}
+
+// Published reports whether the article is out at the given time, that is,
+// it isn't a draft and its publication date, if any, has passed.
+func (m FrontMatter) Published(now time.Time) bool {
+	return !m.Draft && !m.PublishAt.After(now)
+}
+
+// LastModified returns the date of the latest revision of the article.
+func (m FrontMatter) LastModified() time.Time {
+	if m.UpdatedAt.After(m.CreatedAt) {
+		return m.UpdatedAt
+	}
+
+	return m.CreatedAt
+}
+
+// TagURL returns the path of the page listing the articles of a tag, which
+// is escaped as tags like "c#" may hold characters URLs reserve.
+func TagURL(tag string) string {
+	return "/tags/" + url.PathEscape(tag)
+}
+
+// SeriesURL returns the path of the landing page of a series.
+func SeriesURL(slug string) string {
+	return "/series/" + slug
+}
+
+// Series is a multi-part tutorial, its parts in reading order.
+type Series struct {
+	Language string
+	Name     string
+	Slug     string
+	Articles []Article
+}
+
+// URL returns the path of the landing page of the series.
+func (s Series) URL() string {
+	return i18n.Localize(s.Language, SeriesURL(s.Slug))
+}
+
+// SeriesNavigation places an article within its series.
+type SeriesNavigation struct {
+	Series   Series
+	Part     int // 1-based position of the article
+	Previous *Article
+	Next     *Article
+}
+
+// Progress returns how far into the series the article is, in percent.
+func (n SeriesNavigation) Progress() int {
+	return n.Part * 100 / len(n.Series.Articles)
+}
+
+// Revision is a commit that changed an article.
+type Revision struct {
+	Hash    string
+	Message string
+	Author  string
+	Date    time.Time
+	Diff    []template.HTML // hunks of the changes made to the article
+}
+
+// ShortHash returns the abbreviated hash git shows.
+func (r Revision) ShortHash() string {
+	return r.Hash[:min(len(r.Hash), 7)]
+}
+
+// Subject returns the first line of the commit message.
+func (r Revision) Subject() string {
+	subject, _, _ := strings.Cut(r.Message, "\n")
+
+	return strings.TrimSpace(subject)
+}
+
+// Body returns the commit message without its subject.
+func (r Revision) Body() string {
+	_, body, _ := strings.Cut(r.Message, "\n")
+
+	return strings.TrimSpace(body)
+}
+
+// OpenGraph describes a page to social networks sharing links to it.
+type OpenGraph struct {
+	Type        string // e.g. "website" or "article"
+	Image       string
+	ImageWidth  int // set for the cards made for large previews
+	ImageHeight int
+	ImageAlt    string
+}
+
+// Breadcrumb is one step of the navigation trail of a page.
+type Breadcrumb struct {
+	Name string
+	URL  string
+}
+
+// TOCEntry is a section of an article in its table of contents.
+type TOCEntry struct {
+	ID       string
+	Title    string
+	Children []TOCEntry
+}
@@ -0,0 +1,145 @@
+package articles
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"coding-kittens.com/models"
+	"coding-kittens.com/modules/i18n"
+	"coding-kittens.com/modules/markdown"
+	"github.com/adrg/frontmatter"
+)
+
+const ARTICLES_DIR = "web/_articles"
+
+const ARTICLE_EXTENSION = ".mdx"
+
+// WORDS_PER_MINUTE is the reading speed reading times are estimated with.
+const WORDS_PER_MINUTE = 200
+
+// readingTime returns how many minutes reading wordCount words takes.
+func readingTime(wordCount int) int {
+	return max(1, (wordCount+WORDS_PER_MINUTE/2)/WORDS_PER_MINUTE)
+}
+
+// Heading levels listed in the table of contents.
+const (
+	TOC_MIN_LEVEL = 2
+	TOC_MAX_LEVEL = 4
+)
+
+// tableOfContents nests the headings of an article by level.
+func tableOfContents(headings []markdown.Heading) []models.TOCEntry {
+	var entries []models.TOCEntry
+
+	for _, heading := range headings {
+		if heading.ID == "" || heading.Level < TOC_MIN_LEVEL || heading.Level > TOC_MAX_LEVEL {
+			continue
+		}
+
+		// Walk down the last entries to the parent level of the heading
+		level := &entries
+		for depth := TOC_MIN_LEVEL; depth < heading.Level && len(*level) > 0; depth++ {
+			level = &(*level)[len(*level)-1].Children
+		}
+
+		*level = append(*level, models.TOCEntry{ID: heading.ID, Title: heading.Title})
+	}
+
+	return entries
+}
+
+// loadArticle parses the front matter of an article and renders its body,
+// reporting errors under dir.
+func loadArticle(files Files, dir string, fileInfo FileInfo) (models.Article, error) {
+	filePath := path.Join(append(fileInfo.Path, fileInfo.FileName)...)
+	displayPath := path.Join(dir, filePath)
+
+	file, err := fs.ReadFile(files.Articles, filePath)
+	if err != nil {
+		return models.Article{}, err
+	}
+
+	var matter models.FrontMatter
+
+	body, err := frontmatter.Parse(bytes.NewReader(file), &matter)
+	if err != nil {
+		return models.Article{}, fmt.Errorf("%s: invalid front matter: %w", displayPath, err)
+	}
+
+	matter.Tags = normalizeTags(matter.Tags)
+
+	// Scheduled articles are dated by their publication, the others by
+	// their commits when the front matter leaves the dates out
+	if matter.CreatedAt.IsZero() {
+		matter.CreatedAt = matter.PublishAt
+	}
+
+	if matter.CreatedAt.IsZero() {
+		matter.CreatedAt = fileInfo.CreatedAt
+	}
+
+	if matter.UpdatedAt.IsZero() {
+		matter.UpdatedAt = fileInfo.ModifiedAt
+	}
+
+	document, err := markdown.Render(markdown.Source{
+		Path: displayPath,
+		Line: bytes.Count(file[:len(file)-len(body)], []byte("\n")) + 1,
+		Body: body,
+
+		Templates:    files.Templates,
+		StaticAssets: files.Static,
+		Examples:     files.Examples,
+	})
+	if err != nil {
+		return models.Article{}, err
+	}
+
+	titles := make([]string, 0, len(document.Headings))
+	for _, heading := range document.Headings {
+		titles = append(titles, heading.Title)
+	}
+
+	excerpt := strings.TrimSpace(matter.ShortDescription)
+	if excerpt == "" {
+		excerpt = document.Excerpt
+	}
+
+	wordCount := len(strings.Fields(document.Text))
+
+	// Translations are named after the original, e.g. slug.es.mdx
+	slug := strings.TrimSuffix(fileInfo.FileName, ARTICLE_EXTENSION)
+	language := i18n.DEFAULT_LANGUAGE
+
+	if extension := path.Ext(slug); i18n.Supported(strings.TrimPrefix(extension, ".")) {
+		language = strings.TrimPrefix(extension, ".")
+		slug = strings.TrimSuffix(slug, extension)
+	}
+
+	translationKey := articleKey(strings.Join(fileInfo.Path, "/"), slug)
+
+	if matter.Slug != "" {
+		slug = matter.Slug
+	}
+
+	return models.Article{
+		Slug:           slug,
+		Language:       language,
+		TranslationKey: translationKey,
+		Source:         displayPath,
+		Category:       strings.Join(fileInfo.Path, "/"),
+		Categories:     fileInfo.Path,
+		Data:           matter,
+		Content:        document.HTML,
+		Text:           document.Text,
+		Headings:       titles,
+		TOC:            tableOfContents(document.Headings),
+		Excerpt:        excerpt,
+		WordCount:      wordCount,
+		ReadingTime:    readingTime(wordCount),
+	}, nil
+}
@@ -1,5 +1,369 @@
 package articles
 
-import "embed"
+import (
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strings"
+	"time"
 
-var ArticlesFS embed.FS
\ No newline at end of file
+	"coding-kittens.com/models"
+	"coding-kittens.com/modules/i18n"
+	"coding-kittens.com/modules/search"
+)
+
+// Snapshot is an immutable index of every article of a language, built once
+// and shared by all the requests until the next rebuild.
+type Snapshot struct {
+	language    string
+	articles    []models.Article
+	byKey       map[string]int
+	bySlug      map[string]int
+	byCategory  map[string][]int
+	byTag       map[string][]int
+	bySeries    map[string][]int
+	seriesNames map[string]string // display name by series slug
+	search      *search.Index
+	related     [][]int
+	errors      []error
+	builtAt     time.Time
+
+	// Drafts and scheduled articles, left out of every listing but reachable
+	// through preview links
+	unpublished     map[string]models.Article
+	nextPublication time.Time
+
+	// Snapshots of every language and the versions of every article by
+	// translation key, shared by all of them
+	languages    map[string]*Snapshot
+	translations map[string][]models.Article
+
+	// Revisions of the articles, nil when they aren't in a git repository
+	history *historyIndex
+
+	// Directory the articles were read from, e.g. web/_articles
+	dir string
+}
+
+// Files are what the articles are built from: their sources, and the
+// component partials, static files and code examples their bodies are
+// rendered with, which default to those of the markdown package.
+type Files struct {
+	Dir       string // directory of the articles shown in errors, ARTICLES_DIR when ""
+	Articles  fs.FS
+	Templates fs.FS
+	Static    fs.FS
+	Examples  fs.FS
+}
+
+// Options change how the articles are indexed.
+type Options struct {
+	// ShowUnpublished lists drafts and scheduled articles like any other
+	// article, e.g. while writing them in debug mode
+	ShowUnpublished bool
+}
+
+func articleKey(category string, slug string) string {
+	return category + "/" + slug
+}
+
+// buildSnapshot crawls the articles of files, parses every article and
+// renders its body.
+// Articles that fail to load are left out and reported in Errors. It returns
+// the snapshot of the default language, which leads to the others.
+func buildSnapshot(files Files, options Options) *Snapshot {
+	builtAt := time.Now()
+
+	byLanguage := map[string][]models.Article{}
+	unpublished := map[string]map[string]models.Article{}
+	translations := map[string][]models.Article{}
+
+	var errors []error
+	var nextPublication time.Time
+
+	history := currentHistory()
+
+	dir := files.Dir
+	if dir == "" {
+		dir = ARTICLES_DIR
+	}
+
+	for fileInfo := range FileCrawler(files.Articles, ".", nil) {
+		if len(fileInfo.Path) == 0 || path.Ext(fileInfo.FileName) != ARTICLE_EXTENSION {
+			continue
+		}
+
+		if history != nil {
+			fileInfo.CreatedAt, fileInfo.ModifiedAt = history.dates(path.Join(append(fileInfo.Path, fileInfo.FileName)...))
+		}
+
+		article, err := loadArticle(files, dir, fileInfo)
+		if err != nil {
+			log.Println("Error loading article:", err)
+			errors = append(errors, err)
+			continue
+		}
+
+		if !options.ShowUnpublished && !article.Data.Published(builtAt) {
+			if unpublished[article.Language] == nil {
+				unpublished[article.Language] = map[string]models.Article{}
+			}
+
+			unpublished[article.Language][articleKey(article.Category, article.Slug)] = article
+
+			publishAt := article.Data.PublishAt
+			if !article.Data.Draft && (nextPublication.IsZero() || publishAt.Before(nextPublication)) {
+				nextPublication = publishAt
+			}
+
+			continue
+		}
+
+		byLanguage[article.Language] = append(byLanguage[article.Language], article)
+		translations[article.TranslationKey] = append(translations[article.TranslationKey], article)
+	}
+
+	languages := map[string]*Snapshot{}
+
+	for _, language := range i18n.LANGUAGES {
+		snapshot := indexArticles(byLanguage[language])
+
+		snapshot.language = language
+		snapshot.builtAt = builtAt
+		snapshot.errors = errors
+		snapshot.unpublished = unpublished[language]
+		snapshot.nextPublication = nextPublication
+		snapshot.languages = languages
+		snapshot.translations = translations
+		snapshot.history = history
+		snapshot.dir = dir
+
+		languages[language] = snapshot
+	}
+
+	return languages[i18n.DEFAULT_LANGUAGE]
+}
+
+// indexArticles builds the indexes of the articles of a single language.
+func indexArticles(articles []models.Article) *Snapshot {
+	snapshot := &Snapshot{
+		articles:   articles,
+		byKey:      map[string]int{},
+		bySlug:     map[string]int{},
+		byCategory: map[string][]int{},
+		byTag:      map[string][]int{},
+	}
+
+	sortByDate(snapshot.articles)
+
+	documents := make([]search.Document, 0, len(snapshot.articles))
+
+	for i, article := range snapshot.articles {
+		snapshot.byKey[articleKey(article.Category, article.Slug)] = i
+
+		// Index the article under every level of its category chain so parent
+		// categories list the articles of their subcategories too
+		for level := range article.Categories {
+			category := strings.Join(article.Categories[:level+1], "/")
+			snapshot.byCategory[category] = append(snapshot.byCategory[category], i)
+		}
+
+		if _, ok := snapshot.bySlug[article.Slug]; !ok {
+			snapshot.bySlug[article.Slug] = i
+		}
+
+		for _, tag := range article.Data.Tags {
+			snapshot.byTag[tag] = append(snapshot.byTag[tag], i)
+		}
+
+		documents = append(documents, search.Document{
+			Title:       article.Data.Title,
+			Headings:    append(append([]string(nil), article.Headings...), article.Data.Tags...),
+			Description: article.Data.Subtitle + " " + article.Excerpt,
+			Body:        article.Text,
+		})
+	}
+
+	snapshot.bySeries, snapshot.seriesNames = seriesIndex(snapshot.articles)
+	snapshot.search = search.NewIndex(documents)
+	snapshot.related = relatedArticles(snapshot.articles)
+
+	return snapshot
+}
+
+func (s *Snapshot) collect(indexes []int) []models.Article {
+	articles := make([]models.Article, 0, len(indexes))
+
+	for _, i := range indexes {
+		articles = append(articles, s.articles[i])
+	}
+
+	return articles
+}
+
+// All returns every article, newest first.
+func (s *Snapshot) All() []models.Article {
+	return append([]models.Article(nil), s.articles...)
+}
+
+// Get returns the article with the given category and slug.
+func (s *Snapshot) Get(category string, slug string) (models.Article, bool) {
+	i, ok := s.byKey[articleKey(category, slug)]
+	if !ok {
+		return models.Article{}, false
+	}
+
+	return s.articles[i], true
+}
+
+// BySlug returns the newest article with the given slug in any category.
+func (s *Snapshot) BySlug(slug string) (models.Article, bool) {
+	i, ok := s.bySlug[slug]
+	if !ok {
+		return models.Article{}, false
+	}
+
+	return s.articles[i], true
+}
+
+// ByCategory returns the articles of a category and its subcategories,
+// newest first. Nested categories are joined with "/", e.g. "frontend/css".
+func (s *Snapshot) ByCategory(category string) []models.Article {
+	return s.collect(s.byCategory[category])
+}
+
+// ByTag returns the articles tagged with tag, newest first.
+func (s *Snapshot) ByTag(tag string) []models.Article {
+	return s.collect(s.byTag[NormalizeTag(tag)])
+}
+
+// ByDate returns the articles created within [from, to), newest first. A zero
+// bound leaves that side of the range open.
+func (s *Snapshot) ByDate(from time.Time, to time.Time) []models.Article {
+	var articles []models.Article
+
+	for _, article := range s.articles {
+		createdAt := article.Data.CreatedAt
+
+		if (!from.IsZero() && createdAt.Before(from)) || (!to.IsZero() && !createdAt.Before(to)) {
+			continue
+		}
+
+		articles = append(articles, article)
+	}
+
+	return articles
+}
+
+// HasCategory reports whether any article lives under category.
+func (s *Snapshot) HasCategory(category string) bool {
+	_, ok := s.byCategory[category]
+
+	return ok
+}
+
+// Subcategories returns the direct children of a category, or the top level
+// categories when parent is empty.
+func (s *Snapshot) Subcategories(parent string) []string {
+	var subcategories []string
+
+	for _, category := range s.Categories() {
+		name := category
+
+		if parent != "" {
+			if !strings.HasPrefix(category, parent+"/") {
+				continue
+			}
+			name = strings.TrimPrefix(category, parent+"/")
+		}
+
+		if !strings.Contains(name, "/") {
+			subcategories = append(subcategories, category)
+		}
+	}
+
+	return subcategories
+}
+
+// Categories returns every category at every level, sorted alphabetically.
+func (s *Snapshot) Categories() []string {
+	categories := make([]string, 0, len(s.byCategory))
+
+	for category := range s.byCategory {
+		categories = append(categories, category)
+	}
+
+	sort.Strings(categories)
+
+	return categories
+}
+
+// Errors returns the problems found while loading the articles.
+func (s *Snapshot) Errors() []error {
+	return s.errors
+}
+
+// Unpublished returns the draft or scheduled article with the given category
+// and slug, for previews.
+func (s *Snapshot) Unpublished(category string, slug string) (models.Article, bool) {
+	article, ok := s.unpublished[articleKey(category, slug)]
+
+	return article, ok
+}
+
+// File returns the article, published or not, read from the file at
+// filePath under the articles directory without its extensions, e.g.
+// css/slug for css/slug.mdx and its translations.
+func (s *Snapshot) File(filePath string) (models.Article, bool) {
+	for _, article := range s.articles {
+		if article.TranslationKey == filePath {
+			return article, true
+		}
+	}
+
+	for _, article := range s.unpublished {
+		if article.TranslationKey == filePath {
+			return article, true
+		}
+	}
+
+	return models.Article{}, false
+}
+
+// NextPublication returns when the next scheduled article is due, or the
+// zero time when none is.
+func (s *Snapshot) NextPublication() time.Time {
+	return s.nextPublication
+}
+
+// BuiltAt returns when the snapshot was built.
+func (s *Snapshot) BuiltAt() time.Time {
+	return s.builtAt
+}
+
+// Language returns the snapshot of the articles written in language, which
+// is empty when there are none.
+func (s *Snapshot) Language(language string) *Snapshot {
+	if snapshot, ok := s.languages[language]; ok {
+		return snapshot
+	}
+
+	return s.languages[i18n.DEFAULT_LANGUAGE]
+}
+
+// Translations returns the versions of an article in every language it is
+// written in, itself included, in the order of the languages.
+func (s *Snapshot) Translations(article models.Article) []models.Article {
+	var translations []models.Article
+
+	for _, language := range i18n.LANGUAGES {
+		for _, translation := range s.translations[article.TranslationKey] {
+			if translation.Language == language {
+				translations = append(translations, translation)
+			}
+		}
+	}
+
+	return translations
+}
@@ -1,61 +1,53 @@
 package articles
 
 import (
-	"path/filepath"
+	"io/fs"
+	"path"
 	"time"
 )
 
 type FileInfo struct {
-	Path       []string
+	Path       []string // category chain, e.g. ["frontend", "css"]
 	FileName   string
-	CreatedAt  time.Time
-	ModifiedAt time.Time
+	CreatedAt  time.Time // first commit of the file, zero outside a git repository
+	ModifiedAt time.Time // last commit of the file, zero outside a git repository
 }
 
-func FileCrawler(dir string, path []string) <-chan FileInfo {
+// FileCrawler walks dir recursively and sends every file it finds along
+// with the chain of directories leading to it.
+func FileCrawler(fsys fs.FS, dir string, categories []string) <-chan FileInfo {
 	fileInfoChan := make(chan FileInfo)
 
 	go func() {
 		defer close(fileInfoChan)
 
-		files, err := ArticlesFS.ReadDir(dir)
-		if err != nil {
-			return
-		}
-
-		for _, file := range files {
-			if file.IsDir() {
-				subPath := append(path, file.Name())
-				subDir := filepath.Join(dir, file.Name())
-				fileInfoChan <- <-FileCrawler(subDir, subPath)
-			} else {
-				filePath := filepath.Join(dir, file.Name())
-				
-				f, err := ArticlesFS.Open(filePath)
-				if err != nil {
-					continue
-				}
-
-				defer f.Close()
-
-				fileInfo, err := f.Stat()
-				
-				if err != nil {
-					continue
-				}
-				if err != nil {
-					continue
-				}
-
-				fileInfoChan <- FileInfo{
-					Path:       path,
-					FileName:   file.Name(),
-					CreatedAt:  fileInfo.ModTime(),
-					ModifiedAt: fileInfo.ModTime(),
-				}
-			}
-		}
+		crawl(fsys, dir, categories, fileInfoChan)
 	}()
 
 	return fileInfoChan
-}
\ No newline at end of file
+}
+
+func crawl(fsys fs.FS, dir string, categories []string, fileInfoChan chan<- FileInfo) {
+	files, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return
+	}
+
+	for _, file := range files {
+		// Copy the chain so sibling directories never share the same backing array
+		chain := make([]string, len(categories), len(categories)+1)
+		copy(chain, categories)
+
+		if file.IsDir() {
+			crawl(fsys, path.Join(dir, file.Name()), append(chain, file.Name()), fileInfoChan)
+			continue
+		}
+
+		// Modification times say nothing about embedded files, so the dates
+		// are filled in from the git history instead
+		fileInfoChan <- FileInfo{
+			Path:     chain,
+			FileName: file.Name(),
+		}
+	}
+}
@@ -2,48 +2,53 @@ package articles
 
 import (
 	"sort"
-	"time"
-)
 
-// GetAllArticles retrieves information about all articles based on a query.
-func GetAllArticles(query map[string]string) []FileInfo {
-	var articles []FileInfo
+	"coding-kittens.com/models"
+)
 
-	for fileInfo := range FileCrawler("web/_articles", nil) {
-		category := fileInfo.Path[0]
+// GetArticle returns a single article of the current snapshot.
+func GetArticle(category string, slug string) (models.Article, bool) {
+	return Current().Get(category, slug)
+}
 
-		if query["category"] != "" && query["category"] != category {
-			continue
-		}
+// GetAllArticles retrieves information about all articles based on a query.
+// The "language" of the query defaults to the default language.
+func GetAllArticles(query map[string]string) []models.Article {
+	snapshot := Current().Language(query["language"])
 
-		articles = append(articles, FileInfo{
-			Path:       fileInfo.Path,
-			FileName:   fileInfo.FileName,
-			CreatedAt:  fileInfo.CreatedAt,
-			ModifiedAt: fileInfo.ModifiedAt,
-		})
+	if query["category"] != "" {
+		return snapshot.ByCategory(query["category"])
 	}
 
-	return articles
+	return snapshot.All()
 }
 
 const DEFAULT_MAX_OUTPUT_COUNT = 5
 
-func compareDesc(a, b time.Time) bool {
-	return a.After(b)
+// sortByDate orders articles from newest to oldest using their front matter
+// dates, falling back to the slug so the order is stable.
+func sortByDate(articles []models.Article) {
+	sort.SliceStable(articles, func(i, j int) bool {
+		a, b := articles[i].Data, articles[j].Data
+
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+
+		if !a.UpdatedAt.Equal(b.UpdatedAt) {
+			return a.UpdatedAt.After(b.UpdatedAt)
+		}
+
+		return articles[i].Slug < articles[j].Slug
+	})
 }
 
 // getLatestContent retrieves the latest content based on creation date.
-func GetLatestContent(maxOutputCount int) ([]FileInfo, error) {
-	query := map[string]string{} // Empty query for all articles
+func GetLatestContent(language string, maxOutputCount int) ([]models.Article, error) {
+	query := map[string]string{"language": language} // All the articles of the language
 
 	allArticles := GetAllArticles(query)
 
-	// Sort articles by creation time in descending order
-	sort.Slice(allArticles, func(i, j int) bool {
-		return compareDesc(allArticles[i].CreatedAt, allArticles[j].CreatedAt)
-	})
-
 	// Limit the output to the specified count or the default count
 	outputCount := maxOutputCount
 	if outputCount <= 0 || outputCount > len(allArticles) {
@@ -51,4 +56,4 @@ func GetLatestContent(maxOutputCount int) ([]FileInfo, error) {
 	}
 
 	return allArticles[:outputCount], nil
-}
\ No newline at end of file
+}
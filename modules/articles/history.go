@@ -0,0 +1,328 @@
+package articles
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"log"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"coding-kittens.com/models"
+	"coding-kittens.com/modules/markdown"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// DIFF_LANGUAGE is the language the changes to the articles are highlighted
+// as.
+const DIFF_LANGUAGE = "markdown"
+
+// History reads the revisions of the articles from the git repository their
+// directory belongs to.
+type History struct {
+	dir        string
+	repository *git.Repository
+	prefix     string     // directory of the articles within the work tree, "" for its root
+	mutex      sync.Mutex // go-git doesn't read objects concurrently
+	index      *historyIndex
+}
+
+// historyIndex holds the changes made to every article up to a commit,
+// newest first, by the path of the article under the articles directory.
+type historyIndex struct {
+	history *History
+	head    plumbing.Hash
+	changes map[string][]articleChange
+}
+
+type articleChange struct {
+	commit *object.Commit
+	change *object.Change
+}
+
+// OpenHistory reads the history of the articles under dir, which has to be
+// within a git work tree.
+func OpenHistory(dir string) (*History, error) {
+	absolute, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	repository, err := git.PlainOpenWithOptions(absolute, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, err
+	}
+
+	worktree, err := repository.Worktree()
+	if err != nil {
+		return nil, err
+	}
+
+	prefix, err := filepath.Rel(worktree.Filesystem.Root(), absolute)
+	if err != nil {
+		return nil, err
+	}
+
+	if prefix = filepath.ToSlash(prefix); prefix == "." {
+		prefix = ""
+	}
+
+	return &History{dir: dir, repository: repository, prefix: prefix}, nil
+}
+
+// current returns the index of the changes up to HEAD, indexing them again
+// when HEAD has moved since the last time.
+func (h *History) current() (*historyIndex, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	head, err := h.repository.Head()
+	if err != nil {
+		return nil, err
+	}
+
+	if h.index != nil && h.index.head == head.Hash() {
+		return h.index, nil
+	}
+
+	index, err := h.build(head.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	h.index = index
+
+	return index, nil
+}
+
+func (h *History) build(head plumbing.Hash) (*historyIndex, error) {
+	commits, err := h.repository.Log(&git.LogOptions{From: head, Order: git.LogOrderCommitterTime})
+	if err != nil {
+		return nil, err
+	}
+	defer commits.Close()
+
+	index := &historyIndex{history: h, head: head, changes: map[string][]articleChange{}}
+
+	// Older changes to renamed articles belong to their current path
+	renamed := map[string]string{}
+
+	err = commits.ForEach(func(commit *object.Commit) error {
+		// Merges repeat the changes of the commits they bring in
+		if commit.NumParents() > 1 {
+			return nil
+		}
+
+		tree, err := h.articlesTree(commit)
+		if err != nil {
+			return err
+		}
+
+		var parentTree *object.Tree
+
+		if commit.NumParents() == 1 {
+			parent, err := commit.Parent(0)
+			if err != nil {
+				return err
+			}
+
+			if parentTree, err = h.articlesTree(parent); err != nil {
+				return err
+			}
+		}
+
+		if tree == nil && parentTree == nil || tree != nil && parentTree != nil && tree.Hash == parentTree.Hash {
+			return nil
+		}
+
+		changes, err := object.DiffTreeWithOptions(context.Background(), parentTree, tree, object.DefaultDiffTreeOptions)
+		if err != nil {
+			return err
+		}
+
+		for _, change := range changes {
+			name := change.To.Name
+			if name == "" {
+				name = change.From.Name
+			}
+
+			if current, ok := renamed[name]; ok {
+				name = current
+			}
+
+			if change.From.Name != "" && change.To.Name != "" && change.From.Name != change.To.Name {
+				renamed[change.From.Name] = name
+			}
+
+			index.changes[name] = append(index.changes[name], articleChange{commit: commit, change: change})
+		}
+
+		return nil
+	})
+
+	return index, err
+}
+
+// articlesTree returns the tree of the articles directory at commit, or nil
+// when it didn't exist yet.
+func (h *History) articlesTree(commit *object.Commit) (*object.Tree, error) {
+	tree, err := commit.Tree()
+	if err != nil || h.prefix == "" {
+		return tree, err
+	}
+
+	tree, err = tree.Tree(h.prefix)
+	if errors.Is(err, object.ErrDirectoryNotFound) {
+		return nil, nil
+	}
+
+	return tree, err
+}
+
+// dates returns when the article at filePath was first and last committed,
+// or zero times when it never was.
+func (i *historyIndex) dates(filePath string) (time.Time, time.Time) {
+	changes := i.changes[filePath]
+	if len(changes) == 0 {
+		return time.Time{}, time.Time{}
+	}
+
+	return changes[len(changes)-1].commit.Author.When, changes[0].commit.Author.When
+}
+
+// revisions returns the commits that changed the article at filePath, newest
+// first, along with their diffs.
+func (i *historyIndex) revisions(filePath string) []models.Revision {
+	i.history.mutex.Lock()
+	defer i.history.mutex.Unlock()
+
+	var revisions []models.Revision
+
+	for _, change := range i.changes[filePath] {
+		revisions = append(revisions, models.Revision{
+			Hash:    change.commit.Hash.String(),
+			Message: change.commit.Message,
+			Author:  change.commit.Author.Name,
+			Date:    change.commit.Author.When,
+			Diff:    diffHunks(change.change),
+		})
+	}
+
+	return revisions
+}
+
+// diffHunks renders every hunk of the unified diff of change as a code block
+// captioned with its hunk header.
+func diffHunks(change *object.Change) []template.HTML {
+	patch, err := change.Patch()
+	if err != nil {
+		log.Println("Error diffing article:", err)
+		return nil
+	}
+
+	var unified strings.Builder
+	if err := diff.NewUnifiedEncoder(&unified, diff.DefaultContextLines).Encode(patch); err != nil {
+		log.Println("Error diffing article:", err)
+		return nil
+	}
+
+	var hunks []template.HTML
+	var header string
+	var lines []markdown.DiffLine
+
+	flush := func() {
+		if len(lines) > 0 {
+			hunks = append(hunks, markdown.Diff(DIFF_LANGUAGE, header, lines))
+		}
+
+		lines = nil
+	}
+
+	for _, line := range strings.Split(unified.String(), "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			flush()
+			header = line
+		case header == "":
+			// File headers come before the first hunk
+		case strings.HasPrefix(line, "+"):
+			lines = append(lines, markdown.DiffLine{Text: line[1:], Added: true})
+		case strings.HasPrefix(line, "-"):
+			lines = append(lines, markdown.DiffLine{Text: line[1:], Removed: true})
+		case strings.HasPrefix(line, " "):
+			lines = append(lines, markdown.DiffLine{Text: line[1:]})
+		}
+	}
+
+	flush()
+
+	return hunks
+}
+
+var history atomic.Pointer[History]
+
+// UseHistory dates the articles and lists their revisions from the git
+// history of dir, the directory the articles are read from, from the next
+// rebuild on. Articles read from elsewhere, dir being "", aren't.
+func UseHistory(dir string) {
+	if current := history.Load(); current != nil && current.dir == dir {
+		return
+	}
+
+	if dir == "" {
+		history.Store(nil)
+		return
+	}
+
+	opened, err := OpenHistory(dir)
+	if err != nil {
+		log.Printf("No git history for %s: %v", dir, err)
+		opened = &History{dir: dir}
+	}
+
+	history.Store(opened)
+}
+
+// currentHistory returns the index of the changes to the articles, or nil
+// when they aren't in a git repository.
+func currentHistory() *historyIndex {
+	current := history.Load()
+	if current == nil || current.repository == nil {
+		return nil
+	}
+
+	index, err := current.current()
+	if err != nil {
+		log.Println("Error reading git history:", err)
+		return nil
+	}
+
+	return index
+}
+
+// sourcePath returns the path of the file of article under the articles
+// directory.
+func (s *Snapshot) sourcePath(article models.Article) string {
+	return strings.TrimPrefix(article.Source, s.dir+"/")
+}
+
+// HasHistory reports whether article has been committed to git.
+func (s *Snapshot) HasHistory(article models.Article) bool {
+	return s.history != nil && len(s.history.changes[s.sourcePath(article)]) > 0
+}
+
+// History returns the commits that changed article, newest first, along with
+// their diffs.
+func (s *Snapshot) History(article models.Article) []models.Revision {
+	if s.history == nil {
+		return nil
+	}
+
+	return s.history.revisions(s.sourcePath(article))
+}
@@ -0,0 +1,109 @@
+package articles
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestHistory(t *testing.T) {
+	dir := t.TempDir()
+
+	repository, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	worktree, err := repository.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commit := func(message string, when time.Time) {
+		t.Helper()
+
+		signature := &object.Signature{Name: "Kitten", Email: "kitten@coding-kittens.com", When: when}
+		if _, err := worktree.Commit(message, &git.CommitOptions{Author: signature, Committer: signature}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write := func(name string, data string) {
+		t.Helper()
+
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := worktree.Add(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	created := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	edited := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+	renamed := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	body := "---\ntitle: Navbar\n---\n\nA sticky navbar shrinking on scroll.\n"
+
+	write("_articles/css/old.mdx", body)
+	write("README.md", "Coding kittens")
+	commit("Add the navbar article", created)
+
+	// Commits outside the articles directory don't count
+	write("README.md", "Coding kittens blog")
+	commit("Update the readme", edited)
+
+	if err := os.Rename(filepath.Join(dir, "_articles/css/old.mdx"), filepath.Join(dir, "_articles/css/new.mdx")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := worktree.Remove("_articles/css/old.mdx"); err != nil {
+		t.Fatal(err)
+	}
+
+	write("_articles/css/new.mdx", body)
+	commit("Rename the navbar article", renamed)
+
+	history, err := OpenHistory(filepath.Join(dir, "_articles"))
+	if err != nil {
+		t.Fatalf("OpenHistory() error = %v", err)
+	}
+
+	index, err := history.current()
+	if err != nil {
+		t.Fatalf("current() error = %v", err)
+	}
+
+	// The rename keeps the date the article was first committed
+	if first, last := index.dates("css/new.mdx"); !first.Equal(created) || !last.Equal(renamed) {
+		t.Errorf("dates() = %v, %v, want %v, %v", first, last, created, renamed)
+	}
+
+	if first, last := index.dates("css/missing.mdx"); !first.IsZero() || !last.IsZero() {
+		t.Errorf("dates() of an uncommitted article = %v, %v, want zero times", first, last)
+	}
+
+	revisions := index.revisions("css/new.mdx")
+	if len(revisions) != 2 || revisions[0].Message != "Rename the navbar article" || revisions[1].Message != "Add the navbar article" {
+		t.Fatalf("revisions() = %+v, want the rename and the first commit", revisions)
+	}
+
+	if len(revisions[1].Diff) != 1 {
+		t.Errorf("revisions() diff = %d hunks, want 1", len(revisions[1].Diff))
+	}
+
+	// The index is reused until HEAD moves
+	if again, _ := history.current(); again != index {
+		t.Error("current() indexed the history again without new commits")
+	}
+}
@@ -0,0 +1,152 @@
+package articles
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"coding-kittens.com/models"
+	"coding-kittens.com/modules/search"
+)
+
+// Weights of the signals related articles are scored with.
+const (
+	RELATED_TAG_WEIGHT      = 1.0 // per shared tag
+	RELATED_CATEGORY_WEIGHT = 0.5 // per shared level of the category chain
+	RELATED_TEXT_WEIGHT     = 3.0 // times the cosine similarity of the bodies
+)
+
+// RELATED_ARTICLES is how many related articles are kept per article.
+const RELATED_ARTICLES = 3
+
+// termVector is the TF-IDF weight of every term of a text, normalized to a
+// length of 1 so the dot product of two vectors is their cosine similarity.
+type termVector map[string]float64
+
+func termVectors(articles []models.Article) []termVector {
+	frequencies := make([]map[string]int, len(articles))
+	documentFrequency := map[string]int{}
+
+	for i, article := range articles {
+		frequencies[i] = map[string]int{}
+
+		for _, term := range search.Terms(article.Data.Title + " " + article.Text) {
+			if frequencies[i][term] == 0 {
+				documentFrequency[term]++
+			}
+
+			frequencies[i][term]++
+		}
+	}
+
+	vectors := make([]termVector, len(articles))
+
+	for i, terms := range frequencies {
+		vector := termVector{}
+		length := 0.0
+
+		for term, frequency := range terms {
+			weight := (1 + math.Log(float64(frequency))) * math.Log(float64(len(articles))/float64(documentFrequency[term]))
+			if weight <= 0 {
+				continue
+			}
+
+			vector[term] = weight
+			length += weight * weight
+		}
+
+		for term := range vector {
+			vector[term] /= math.Sqrt(length)
+		}
+
+		vectors[i] = vector
+	}
+
+	return vectors
+}
+
+func (v termVector) similarity(other termVector) float64 {
+	if len(other) < len(v) {
+		v, other = other, v
+	}
+
+	dot := 0.0
+	for term, weight := range v {
+		dot += weight * other[term]
+	}
+
+	return dot
+}
+
+// sharedPrefix counts the leading categories two chains have in common.
+func sharedPrefix(a []string, b []string) int {
+	shared := 0
+
+	for shared < len(a) && shared < len(b) && a[shared] == b[shared] {
+		shared++
+	}
+
+	return shared
+}
+
+func relatedScore(a models.Article, b models.Article, vectors []termVector, i int, j int) float64 {
+	score := RELATED_CATEGORY_WEIGHT * float64(sharedPrefix(a.Categories, b.Categories))
+
+	for _, tag := range a.Data.Tags {
+		for _, other := range b.Data.Tags {
+			if tag == other {
+				score += RELATED_TAG_WEIGHT
+			}
+		}
+	}
+
+	return score + RELATED_TEXT_WEIGHT*vectors[i].similarity(vectors[j])
+}
+
+// relatedArticles ranks, for every article, the other articles by how much
+// they have in common with it.
+func relatedArticles(articles []models.Article) [][]int {
+	vectors := termVectors(articles)
+	related := make([][]int, len(articles))
+
+	for i := range articles {
+		type candidate struct {
+			index int
+			score float64
+		}
+
+		var candidates []candidate
+
+		for j := range articles {
+			if i == j {
+				continue
+			}
+
+			if score := relatedScore(articles[i], articles[j], vectors, i, j); score > 0 {
+				candidates = append(candidates, candidate{index: j, score: score})
+			}
+		}
+
+		// Ties go to the newest article, which comes first in the snapshot
+		sort.SliceStable(candidates, func(a, b int) bool {
+			return candidates[a].score > candidates[b].score
+		})
+
+		for k := 0; k < len(candidates) && k < RELATED_ARTICLES; k++ {
+			related[i] = append(related[i], candidates[k].index)
+		}
+	}
+
+	return related
+}
+
+// Related returns the articles most related to the article with the given
+// category and slug, best first.
+func (s *Snapshot) Related(category string, slug string) []models.Article {
+	i, ok := s.byKey[articleKey(strings.Trim(category, "/"), slug)]
+	if !ok {
+		return nil
+	}
+
+	return s.collect(s.related[i])
+}
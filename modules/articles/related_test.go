@@ -0,0 +1,80 @@
+package articles
+
+import (
+	"reflect"
+	"testing"
+
+	"coding-kittens.com/models"
+)
+
+func TestRelatedArticles(t *testing.T) {
+	article := func(slug string, categories []string, tags []string, text string) models.Article {
+		return models.Article{Slug: slug, Categories: categories, Data: models.FrontMatter{Title: slug, Tags: tags}, Text: text}
+	}
+
+	articles := []models.Article{
+		article("navbar", []string{"frontend", "css"}, []string{"css", "animations"}, "A sticky navbar shrinking on scroll"),
+		article("scroll", []string{"frontend", "css"}, []string{"css", "animations"}, "Scroll driven animations"),
+		article("grid", []string{"frontend", "css"}, []string{"css"}, "Layouts with grid"),
+		article("forms", []string{"frontend", "html"}, nil, "Accessible forms"),
+		article("recursion", []string{"javascript"}, []string{"javascript"}, "Recursion limits of the stack"),
+		article("iteration", []string{"javascript"}, nil, "Turning recursion into iteration to avoid stack limits"),
+		article("unrelated", []string{"go"}, nil, "Goroutines"),
+	}
+
+	related := relatedArticles(articles)
+
+	names := func(i int) []string {
+		var slugs []string
+		for _, j := range related[i] {
+			slugs = append(slugs, articles[j].Slug)
+		}
+
+		return slugs
+	}
+
+	tests := []struct {
+		slug string
+		want []string
+	}{
+		// Shared tags weigh more than the category, ties go to the first
+		{slug: "navbar", want: []string{"scroll", "grid", "forms"}},
+		// A similar body makes up for the lack of shared tags
+		{slug: "iteration", want: []string{"recursion"}},
+		{slug: "unrelated", want: nil},
+	}
+
+	for i, article := range articles {
+		for _, test := range tests {
+			if article.Slug == test.slug {
+				if got := names(i); !reflect.DeepEqual(got, test.want) {
+					t.Errorf("related to %s = %q, want %q", test.slug, got, test.want)
+				}
+			}
+		}
+
+		if len(related[i]) > RELATED_ARTICLES {
+			t.Errorf("related to %s = %d articles, want at most %d", article.Slug, len(related[i]), RELATED_ARTICLES)
+		}
+	}
+}
+
+func TestSharedPrefix(t *testing.T) {
+	tests := []struct {
+		a    []string
+		b    []string
+		want int
+	}{
+		{a: []string{"frontend", "css"}, b: []string{"frontend", "css"}, want: 2},
+		{a: []string{"frontend", "css"}, b: []string{"frontend", "html"}, want: 1},
+		{a: []string{"frontend"}, b: []string{"frontend", "css"}, want: 1},
+		{a: []string{"css"}, b: []string{"frontend", "css"}, want: 0},
+		{a: nil, b: []string{"css"}, want: 0},
+	}
+
+	for _, test := range tests {
+		if got := sharedPrefix(test.a, test.b); got != test.want {
+			t.Errorf("sharedPrefix(%q, %q) = %d, want %d", test.a, test.b, got, test.want)
+		}
+	}
+}
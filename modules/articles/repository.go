@@ -0,0 +1,112 @@
+package articles
+
+import (
+	"io/fs"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Repository keeps the current Snapshot of the articles and swaps it
+// atomically when the articles are rebuilt.
+type Repository struct {
+	fsys      fs.FS
+	options   Options
+	snapshot  atomic.Pointer[Snapshot]
+	mutex     sync.Mutex
+	scheduled *time.Timer // rebuilds the snapshot when the next scheduled article is due
+}
+
+// NewRepository serves the articles of fsys, indexed by Rebuild or Publish.
+func NewRepository(fsys fs.FS, options Options) *Repository {
+	return &Repository{fsys: fsys, options: options}
+}
+
+// Build indexes the articles of files without publishing them, e.g. those of
+// a copy of the content before it is swapped in.
+func (r *Repository) Build(files Files) *Snapshot {
+	return buildSnapshot(files, r.options)
+}
+
+// Rebuild indexes the articles again and publishes the new snapshot.
+func (r *Repository) Rebuild() *Snapshot {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// Errors keep naming the directory of the published articles
+	files := Files{Articles: r.fsys}
+	if current := r.snapshot.Load(); current != nil {
+		files.Dir = current.dir
+	}
+
+	snapshot := r.Build(files)
+	r.publish(snapshot)
+
+	return snapshot
+}
+
+// Publish makes snapshot the current index of the articles.
+func (r *Repository) Publish(snapshot *Snapshot) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.publish(snapshot)
+}
+
+func (r *Repository) publish(snapshot *Snapshot) {
+	r.snapshot.Store(snapshot)
+
+	// Scheduled articles go live on their own once their time comes
+	if r.scheduled != nil {
+		r.scheduled.Stop()
+		r.scheduled = nil
+	}
+
+	if next := snapshot.NextPublication(); !next.IsZero() {
+		r.scheduled = time.AfterFunc(time.Until(next), func() {
+			snapshot := r.Rebuild()
+			log.Printf("Scheduled articles published: %d articles", len(snapshot.articles))
+		})
+	}
+}
+
+// Snapshot returns the current immutable index of articles.
+func (r *Repository) Snapshot() *Snapshot {
+	snapshot := r.snapshot.Load()
+
+	if snapshot == nil {
+		return buildSnapshot(Files{Articles: emptyFS{}}, Options{})
+	}
+
+	return snapshot
+}
+
+// emptyFS is used before the first snapshot is built.
+type emptyFS struct{}
+
+func (emptyFS) Open(name string) (fs.File, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+var defaultRepository atomic.Pointer[Repository]
+
+// Load makes a repository of the articles of fsys the default one used by
+// the package level helpers. It is empty until it is rebuilt or published to.
+func Load(fsys fs.FS, options Options) *Repository {
+	repository := NewRepository(fsys, options)
+	defaultRepository.Store(repository)
+
+	return repository
+}
+
+// Current returns the snapshot of the default repository.
+func Current() *Snapshot {
+	repository := defaultRepository.Load()
+
+	if repository == nil {
+		return buildSnapshot(Files{Articles: emptyFS{}}, Options{})
+	}
+
+	return repository.Snapshot()
+}
@@ -0,0 +1,88 @@
+package articles
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func source(frontMatter string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte("---\n" + frontMatter + "\n---\nBody.")}
+}
+
+func slugs(snapshot *Snapshot) []string {
+	var slugs []string
+	for _, article := range snapshot.All() {
+		slugs = append(slugs, article.Slug)
+	}
+
+	return slugs
+}
+
+func TestUnpublished(t *testing.T) {
+	fsys := fstest.MapFS{
+		"css/published.mdx": source("title: Published\ncreatedAt: 2024-01-20"),
+		"css/past.mdx":      source("title: Past\npublishAt: 2024-02-01T10:00:00Z"),
+		"css/draft.mdx":     source("title: Draft\ndraft: true\npublishAt: 2024-02-01T10:00:00Z"),
+		"css/future.mdx":    source("title: Future\npublishAt: 2099-02-01T10:00:00Z"),
+		"css/later.mdx":     source("title: Later\npublishAt: 2099-03-01T10:00:00Z"),
+	}
+
+	snapshot := NewRepository(fsys, Options{}).Build(Files{Articles: fsys})
+
+	if got := slugs(snapshot); len(got) != 2 || got[0] != "past" || got[1] != "published" {
+		t.Errorf("All() = %q, want the published articles only", got)
+	}
+
+	// Scheduled articles are dated by their publication
+	if past, _ := snapshot.Get("css", "past"); !past.Data.CreatedAt.Equal(time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("CreatedAt = %v, want the publication date", past.Data.CreatedAt)
+	}
+
+	for _, slug := range []string{"draft", "future", "later"} {
+		if _, ok := snapshot.Get("css", slug); ok {
+			t.Errorf("Get(%q) found an unpublished article", slug)
+		}
+
+		if _, ok := snapshot.Unpublished("css", slug); !ok {
+			t.Errorf("Unpublished(%q) = false, want the article for previews", slug)
+		}
+	}
+
+	// Drafts are never due
+	if got := snapshot.NextPublication(); !got.Equal(time.Date(2099, 2, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("NextPublication() = %v, want the earliest scheduled date", got)
+	}
+
+	if got := slugs(NewRepository(fsys, Options{ShowUnpublished: true}).Build(Files{Articles: fsys})); len(got) != 5 {
+		t.Errorf("All() = %q, want every article when showing the unpublished ones", got)
+	}
+}
+
+func TestScheduledPublishing(t *testing.T) {
+	publishAt := time.Now().Add(time.Second).UTC().Format(time.RFC3339)
+
+	fsys := fstest.MapFS{
+		"css/published.mdx": source("title: Published\ncreatedAt: 2024-01-20"),
+		"css/scheduled.mdx": source("title: Scheduled\npublishAt: " + publishAt),
+	}
+
+	repository := NewRepository(fsys, Options{})
+	if got := slugs(repository.Rebuild()); len(got) != 1 {
+		t.Fatalf("All() = %q, want the scheduled article left out", got)
+	}
+
+	// The repository rebuilds itself once the article is due
+	deadline := time.Now().Add(5 * time.Second)
+	for len(slugs(repository.Snapshot())) == 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("the scheduled article wasn't published")
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	if !repository.Snapshot().NextPublication().IsZero() {
+		t.Errorf("NextPublication() = %v, want none left", repository.Snapshot().NextPublication())
+	}
+}
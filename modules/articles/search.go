@@ -0,0 +1,27 @@
+package articles
+
+import (
+	"html/template"
+
+	"coding-kittens.com/models"
+)
+
+// SearchResult is an article matching a search query.
+type SearchResult struct {
+	Article models.Article
+	Snippet template.HTML // excerpt with the matching words in <mark>
+}
+
+// Search returns up to limit articles matching query, most relevant first.
+func (s *Snapshot) Search(query string, limit int) []SearchResult {
+	var results []SearchResult
+
+	for _, result := range s.search.Search(query, limit) {
+		results = append(results, SearchResult{
+			Article: s.articles[result.Document],
+			Snippet: result.Snippet,
+		})
+	}
+
+	return results
+}
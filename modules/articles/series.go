@@ -0,0 +1,128 @@
+package articles
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"coding-kittens.com/models"
+)
+
+// SeriesSlug turns the name of a series into its URL segment, e.g.
+// "Building a blog in Go" into "building-a-blog-in-go".
+func SeriesSlug(name string) string {
+	var sb strings.Builder
+	dash := false
+
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			sb.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+
+	return sb.String()
+}
+
+// seriesIndex groups the articles by series, each one in reading order:
+// seriesOrder first, then creation date for the parts without one.
+func seriesIndex(articles []models.Article) (map[string][]int, map[string]string) {
+	bySeries := map[string][]int{}
+	names := map[string]string{}
+
+	for i, article := range articles {
+		slug := SeriesSlug(article.Data.Series)
+		if slug == "" {
+			continue
+		}
+
+		if _, ok := names[slug]; !ok {
+			names[slug] = strings.TrimSpace(article.Data.Series)
+		}
+
+		bySeries[slug] = append(bySeries[slug], i)
+	}
+
+	for _, indexes := range bySeries {
+		sort.SliceStable(indexes, func(i, j int) bool {
+			a, b := articles[indexes[i]].Data, articles[indexes[j]].Data
+
+			if (a.SeriesOrder == 0) != (b.SeriesOrder == 0) {
+				return a.SeriesOrder != 0
+			}
+
+			if a.SeriesOrder != b.SeriesOrder {
+				return a.SeriesOrder < b.SeriesOrder
+			}
+
+			return a.CreatedAt.Before(b.CreatedAt)
+		})
+	}
+
+	return bySeries, names
+}
+
+// Series returns the series with the given slug.
+func (s *Snapshot) Series(slug string) (models.Series, bool) {
+	indexes, ok := s.bySeries[slug]
+	if !ok {
+		return models.Series{}, false
+	}
+
+	return models.Series{
+		Language: s.language,
+		Name:     s.seriesNames[slug],
+		Slug:     slug,
+		Articles: s.collect(indexes),
+	}, true
+}
+
+// SeriesNavigation returns the previous and next parts of the series of an
+// article, or nil when it isn't part of any.
+func (s *Snapshot) SeriesNavigation(article models.Article) *models.SeriesNavigation {
+	series, ok := s.Series(SeriesSlug(article.Data.Series))
+	if !ok {
+		return nil
+	}
+
+	for i, part := range series.Articles {
+		if part.Category != article.Category || part.Slug != article.Slug {
+			continue
+		}
+
+		navigation := &models.SeriesNavigation{Series: series, Part: i + 1}
+
+		if i > 0 {
+			navigation.Previous = &series.Articles[i-1]
+		}
+
+		if i < len(series.Articles)-1 {
+			navigation.Next = &series.Articles[i+1]
+		}
+
+		return navigation
+	}
+
+	return nil
+}
+
+// AllSeries returns every series, sorted by name.
+func (s *Snapshot) AllSeries() []models.Series {
+	all := make([]models.Series, 0, len(s.bySeries))
+
+	for slug := range s.bySeries {
+		series, _ := s.Series(slug)
+		all = append(all, series)
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Name < all[j].Name
+	})
+
+	return all
+}
@@ -0,0 +1,128 @@
+package articles
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"coding-kittens.com/models"
+)
+
+func TestSeriesSlug(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "Building a blog in Go", want: "building-a-blog-in-go"},
+		{name: "  Modern CSS: Part 1 ", want: "modern-css-part-1"},
+		{name: "Diseño web", want: "diseño-web"},
+		{name: "", want: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := SeriesSlug(test.name); got != test.want {
+				t.Errorf("SeriesSlug(%q) = %q, want %q", test.name, got, test.want)
+			}
+		})
+	}
+}
+
+func TestSeriesIndex(t *testing.T) {
+	day := func(n int) time.Time {
+		return time.Date(2024, 1, n, 0, 0, 0, 0, time.UTC)
+	}
+
+	part := func(slug string, series string, order int, created time.Time) models.Article {
+		return models.Article{Slug: slug, Category: "css", Data: models.FrontMatter{Series: series, SeriesOrder: order, CreatedAt: created}}
+	}
+
+	// Newest first, as in the snapshots
+	articles := []models.Article{
+		part("unordered-new", "Modern CSS", 0, day(9)),
+		part("second", "Modern CSS", 2, day(8)),
+		part("unordered-old", "modern css", 0, day(7)),
+		part("first", "Modern CSS", 1, day(6)),
+		part("alone", "", 0, day(5)),
+		part("other", "Go", 0, day(4)),
+	}
+
+	bySeries, names := seriesIndex(articles)
+
+	order := func(slug string) []string {
+		var slugs []string
+		for _, i := range bySeries[slug] {
+			slugs = append(slugs, articles[i].Slug)
+		}
+
+		return slugs
+	}
+
+	// Numbered parts first, then the others by date
+	if got, want := order("modern-css"), []string{"first", "second", "unordered-old", "unordered-new"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("modern-css = %q, want %q", got, want)
+	}
+
+	if got, want := order("go"), []string{"other"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("go = %q, want %q", got, want)
+	}
+
+	if len(bySeries) != 2 || names["modern-css"] != "Modern CSS" || names["go"] != "Go" {
+		t.Errorf("seriesIndex() = %v, names %q", bySeries, names)
+	}
+}
+
+func TestSeriesNavigation(t *testing.T) {
+	snapshot := &Snapshot{
+		articles: []models.Article{
+			{Slug: "second", Category: "css", Data: models.FrontMatter{Series: "Modern CSS", SeriesOrder: 2}},
+			{Slug: "first", Category: "css", Data: models.FrontMatter{Series: "Modern CSS", SeriesOrder: 1}},
+			{Slug: "third", Category: "css", Data: models.FrontMatter{Series: "Modern CSS", SeriesOrder: 3}},
+			{Slug: "alone", Category: "css"},
+		},
+	}
+	snapshot.bySeries, snapshot.seriesNames = seriesIndex(snapshot.articles)
+
+	tests := []struct {
+		slug     string
+		part     int
+		previous string
+		next     string
+	}{
+		{slug: "first", part: 1, next: "second"},
+		{slug: "second", part: 2, previous: "first", next: "third"},
+		{slug: "third", part: 3, previous: "second"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.slug, func(t *testing.T) {
+			article := snapshot.articles[0]
+			for _, candidate := range snapshot.articles {
+				if candidate.Slug == test.slug {
+					article = candidate
+				}
+			}
+
+			navigation := snapshot.SeriesNavigation(article)
+			if navigation == nil {
+				t.Fatal("SeriesNavigation() = nil")
+			}
+
+			slug := func(article *models.Article) string {
+				if article == nil {
+					return ""
+				}
+
+				return article.Slug
+			}
+
+			if navigation.Part != test.part || slug(navigation.Previous) != test.previous || slug(navigation.Next) != test.next {
+				t.Errorf("SeriesNavigation() = part %d, previous %q, next %q", navigation.Part, slug(navigation.Previous), slug(navigation.Next))
+			}
+		})
+	}
+
+	if navigation := snapshot.SeriesNavigation(snapshot.articles[3]); navigation != nil {
+		t.Errorf("SeriesNavigation() = %+v, want nil outside a series", navigation)
+	}
+}
@@ -0,0 +1,123 @@
+package articles
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// tagAliases merges the different spellings of a topic into a single tag.
+var tagAliases = map[string]string{
+	"js":         "javascript",
+	"es6":        "javascript",
+	"ecmascript": "javascript",
+	"ts":         "typescript",
+	"css3":       "css",
+	"html5":      "html",
+	"golang":     "go",
+	"node":       "nodejs",
+	"node-js":    "nodejs",
+	"perf":       "performance",
+	"a11y":       "accessibility",
+}
+
+// NormalizeTag lowercases a tag, turns whitespace and underscores into dashes
+// and resolves its aliases, so "JS", " javascript " and "Java Script" don't
+// end up as different tags.
+func NormalizeTag(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(tag), "#")))
+
+	var sb strings.Builder
+	dash := false
+
+	for _, r := range tag {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '+' || r == '#':
+			if dash && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			sb.WriteRune(r)
+			dash = false
+		default:
+			dash = true
+		}
+	}
+
+	normalized := sb.String()
+
+	if alias, ok := tagAliases[normalized]; ok {
+		return alias
+	}
+
+	return normalized
+}
+
+// normalizeTags normalizes a list of tags and drops empty and repeated ones.
+func normalizeTags(tags []string) []string {
+	var normalized []string
+	seen := map[string]bool{}
+
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+
+		if tag == "" || seen[tag] {
+			continue
+		}
+
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	return normalized
+}
+
+// TagCount is a tag along with how many articles use it and a weight from 1
+// to TAG_WEIGHTS used to size it in a tag cloud.
+type TagCount struct {
+	Name   string
+	Count  int
+	Weight int
+}
+
+const TAG_WEIGHTS = 5
+
+// Tags returns every tag with its article count, sorted by name.
+func (s *Snapshot) Tags() []TagCount {
+	tags := make([]TagCount, 0, len(s.byTag))
+
+	minCount, maxCount := 0, 0
+
+	for name, indexes := range s.byTag {
+		count := len(indexes)
+
+		if minCount == 0 || count < minCount {
+			minCount = count
+		}
+
+		if count > maxCount {
+			maxCount = count
+		}
+
+		tags = append(tags, TagCount{Name: name, Count: count})
+	}
+
+	for i := range tags {
+		tags[i].Weight = 1
+
+		if maxCount > minCount {
+			tags[i].Weight += (tags[i].Count - minCount) * (TAG_WEIGHTS - 1) / (maxCount - minCount)
+		}
+	}
+
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Name < tags[j].Name
+	})
+
+	return tags
+}
+
+// TagCloud returns the tag counts of the current snapshot of language, for
+// templates.
+func TagCloud(language string) []TagCount {
+	return Current().Language(language).Tags()
+}
@@ -0,0 +1,58 @@
+package articles
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{tag: "css", want: "css"},
+		{tag: " CSS ", want: "css"},
+		{tag: "#CSS", want: "css"},
+		{tag: "Scroll Driven  Animations", want: "scroll-driven-animations"},
+		{tag: "scroll_driven-animations", want: "scroll-driven-animations"},
+		{tag: "--web--", want: "web"},
+		{tag: "JS", want: "javascript"},
+		{tag: "ES6", want: "javascript"},
+		{tag: "node", want: "nodejs"},
+		{tag: "a11y", want: "accessibility"},
+		{tag: "C++", want: "c++"},
+		{tag: "c#", want: "c#"},
+		{tag: "Node.js", want: "node.js"},
+		{tag: "Diseño Web", want: "diseño-web"},
+		{tag: "  ", want: ""},
+		{tag: "#", want: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tag, func(t *testing.T) {
+			if got := NormalizeTag(test.tag); got != test.want {
+				t.Errorf("NormalizeTag(%q) = %q, want %q", test.tag, got, test.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{name: "none", tags: nil, want: nil},
+		{name: "aliases merged", tags: []string{"JS", "javascript", "CSS"}, want: []string{"javascript", "css"}},
+		{name: "empty dropped", tags: []string{"", " # ", "html"}, want: []string{"html"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := normalizeTags(test.tags); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("normalizeTags(%q) = %q, want %q", test.tags, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package check
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"coding-kittens.com/modules/articles"
+	"coding-kittens.com/modules/content"
+	"coding-kittens.com/modules/i18n"
+	"coding-kittens.com/modules/markdown"
+)
+
+// Diagnostic is a problem found in an article source.
+type Diagnostic struct {
+	Path    string
+	Line    int
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	if d.Path == "" {
+		return d.Message
+	}
+
+	if d.Line == 0 {
+		return fmt.Sprintf("%s: %s", d.Path, d.Message)
+	}
+
+	return fmt.Sprintf("%s:%d: %s", d.Path, d.Line, d.Message)
+}
+
+// Content checks every article of articlesFS, drafts and scheduled ones
+// included: their front matter against the schema, and their internal links,
+// anchors and images against the routes, the other articles and staticFS.
+// staticFS holds the files under /static and routes are gin route patterns,
+// e.g. /tags/:tag. dir is the content directory the files were read from,
+// "" for the web directory of the repository, and prefixes the reported paths.
+func Content(dir string, articlesFS fs.FS, staticFS fs.FS, routes []string) []Diagnostic {
+	var diagnostics []Diagnostic
+
+	articlesDir, staticDir := articles.ARTICLES_DIR, STATIC_DIR
+	if dir != "" {
+		articlesDir, staticDir = path.Join(dir, content.ARTICLES_DIR), path.Join(dir, content.STATIC_DIR)
+	}
+
+	sources := map[string]*source{}
+
+	for fileInfo := range articles.FileCrawler(articlesFS, ".", nil) {
+		if len(fileInfo.Path) == 0 || path.Ext(fileInfo.FileName) != articles.ARTICLE_EXTENSION {
+			continue
+		}
+
+		filePath := path.Join(append(fileInfo.Path, fileInfo.FileName)...)
+
+		file, err := fs.ReadFile(articlesFS, filePath)
+		if err != nil {
+			continue
+		}
+
+		src := newSource(path.Join(articlesDir, filePath), file)
+		sources[src.path] = src
+
+		diagnostics = append(diagnostics, src.checkFrontMatter()...)
+	}
+
+	snapshot := articles.NewRepository(articlesFS, articles.Options{ShowUnpublished: true}).Build(articles.Files{Dir: articlesDir, Articles: articlesFS})
+
+	for _, err := range flatten(snapshot.Errors()) {
+		var renderError *markdown.Error
+
+		switch {
+		case errors.As(err, &renderError):
+			diagnostics = append(diagnostics, Diagnostic{Path: renderError.Path, Line: renderError.Line, Message: renderError.Message})
+		case !reportedFrontMatter(diagnostics, err):
+			diagnostics = append(diagnostics, Diagnostic{Message: err.Error()})
+		}
+	}
+
+	resolver := &resolver{snapshot: snapshot, static: staticFS, staticDir: staticDir, routes: routes}
+
+	loaded := map[string]bool{}
+
+	for _, language := range i18n.LANGUAGES {
+		for _, article := range snapshot.Language(language).All() {
+			if src, ok := sources[article.Source]; ok {
+				diagnostics = append(diagnostics, resolver.checkArticle(src, article)...)
+				loaded[src.path] = true
+			}
+		}
+	}
+
+	// The articles that failed to load still have their links checked
+	for _, src := range sources {
+		if !loaded[src.path] {
+			diagnostics = append(diagnostics, resolver.checkSource(src)...)
+		}
+	}
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		if diagnostics[i].Path != diagnostics[j].Path {
+			return diagnostics[i].Path < diagnostics[j].Path
+		}
+
+		return diagnostics[i].Line < diagnostics[j].Line
+	})
+
+	return diagnostics
+}
+
+// flatten splits the joined errors of errs into their parts.
+func flatten(errs []error) []error {
+	var flattened []error
+
+	for _, err := range errs {
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			flattened = append(flattened, flatten(joined.Unwrap())...)
+		} else {
+			flattened = append(flattened, err)
+		}
+	}
+
+	return flattened
+}
+
+// reportedFrontMatter reports whether a load error is about a front matter
+// the schema check already found broken, with a better location.
+func reportedFrontMatter(diagnostics []Diagnostic, err error) bool {
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Path != "" && strings.HasPrefix(err.Error(), diagnostic.Path+": invalid front matter") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,248 @@
+package check
+
+import (
+	"fmt"
+	"html"
+	"io/fs"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"coding-kittens.com/models"
+	"coding-kittens.com/modules/articles"
+	"coding-kittens.com/modules/i18n"
+	"coding-kittens.com/modules/markdown"
+)
+
+const STATIC_DIR = "web/static"
+
+var (
+	linkPattern   = regexp.MustCompile(`\s(href|src|poster)="([^"]*)"`)
+	srcsetPattern = regexp.MustCompile(`\ssrcset="([^"]*)"`)
+	idPattern     = regexp.MustCompile(`\sid="([^"]*)"`)
+)
+
+// links returns every URL an article body points at, as written.
+func links(content string) []string {
+	var urls []string
+
+	for _, match := range linkPattern.FindAllStringSubmatch(content, -1) {
+		urls = append(urls, html.UnescapeString(match[2]))
+	}
+
+	for _, match := range srcsetPattern.FindAllStringSubmatch(content, -1) {
+		for _, candidate := range strings.Split(html.UnescapeString(match[1]), ",") {
+			if fields := strings.Fields(candidate); len(fields) > 0 {
+				urls = append(urls, fields[0])
+			}
+		}
+	}
+
+	return urls
+}
+
+// anchors returns the ids of the elements of an article body.
+func anchors(content string) map[string]bool {
+	ids := map[string]bool{}
+
+	for _, match := range idPattern.FindAllStringSubmatch(content, -1) {
+		ids[html.UnescapeString(match[1])] = true
+	}
+
+	return ids
+}
+
+// resolver finds what internal URLs point at.
+type resolver struct {
+	snapshot  *articles.Snapshot
+	static    fs.FS
+	staticDir string // directory of static, shown in problems
+	routes    []string
+}
+
+// matchRoute reports whether path matches the gin route pattern.
+func matchRoute(pattern string, path string) bool {
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+
+	for i, segment := range patternSegments {
+		if strings.HasPrefix(segment, "*") {
+			return true
+		}
+
+		if i >= len(pathSegments) {
+			return false
+		}
+
+		if !strings.HasPrefix(segment, ":") && segment != pathSegments[i] {
+			return false
+		}
+	}
+
+	return len(patternSegments) == len(pathSegments)
+}
+
+// checkArticle resolves the thumbnail, links, anchors and images of article.
+func (r *resolver) checkArticle(src *source, article models.Article) []Diagnostic {
+	var diagnostics []Diagnostic
+
+	if thumbnail := article.Data.Thumbnail; thumbnail != "" {
+		if problem := r.resolveStatic(thumbnail); problem != "" {
+			diagnostics = append(diagnostics, src.diagnostic(src.keyLines["thumbnail"], "thumbnail "+problem))
+		}
+	}
+
+	return append(diagnostics, r.checkLinks(src, string(article.Content), article.Language, article.Data.Published(time.Now()))...)
+}
+
+// checkSource resolves the links, anchors and images of an article that
+// failed to load, from the markdown of its source as written.
+func (r *resolver) checkSource(src *source) []Diagnostic {
+	body := strings.Join(src.lines[min(src.bodyLine-1, len(src.lines)):], "\n")
+	content := string(markdown.RenderMarkdown([]byte(body)))
+
+	// Translations are named after the original, e.g. slug.es.mdx
+	language := i18n.DEFAULT_LANGUAGE
+	if extension := path.Ext(strings.TrimSuffix(src.path, articles.ARTICLE_EXTENSION)); i18n.Supported(strings.TrimPrefix(extension, ".")) {
+		language = strings.TrimPrefix(extension, ".")
+	}
+
+	return r.checkLinks(src, content, language, false)
+}
+
+// checkLinks resolves the links of the HTML content of an article.
+// Published articles must not link to unpublished ones.
+func (r *resolver) checkLinks(src *source, content string, language string, published bool) []Diagnostic {
+	var diagnostics []Diagnostic
+
+	ids := anchors(content)
+
+	for _, link := range links(content) {
+		if problem := r.resolve(link, language, ids, published); problem != "" {
+			diagnostics = append(diagnostics, src.diagnostic(src.lineOf(link), problem))
+		}
+	}
+
+	return diagnostics
+}
+
+// resolve returns what is wrong with a link of an article, or "" when it
+// points at something that exists. External links are left alone.
+func (r *resolver) resolve(link string, language string, ids map[string]bool, published bool) string {
+	target, err := url.Parse(link)
+	if err != nil {
+		return fmt.Sprintf("malformed link %q", link)
+	}
+
+	if target.Scheme != "" || target.Host != "" || link == "" {
+		return ""
+	}
+
+	// Anchors within the article
+	if target.Path == "" {
+		if target.Fragment != "" && !ids[target.Fragment] {
+			return fmt.Sprintf("no heading or element with id %q for %q", target.Fragment, link)
+		}
+
+		return ""
+	}
+
+	if !strings.HasPrefix(target.Path, "/") {
+		return fmt.Sprintf("relative link %q, internal links start with /", link)
+	}
+
+	if strings.HasPrefix(target.Path, "/static/") {
+		if problem := r.resolveStatic(target.Path); problem != "" {
+			return problem
+		}
+
+		return ""
+	}
+
+	// Resized images point at a static file through the url parameter
+	if target.Path == "/image" {
+		if problem := r.resolveStatic(target.Query().Get("url")); problem != "" {
+			return "image " + problem
+		}
+
+		return ""
+	}
+
+	matched := ""
+	for _, route := range r.routes {
+		if matchRoute(route, target.Path) {
+			matched = route
+			break
+		}
+	}
+
+	if matched == "" {
+		return fmt.Sprintf("no route for %q", link)
+	}
+
+	return r.resolvePage(link, target, published)
+}
+
+// resolvePage looks up the article, category, tag or series a link to one
+// of the pages with parameters points at.
+func (r *resolver) resolvePage(link string, target *url.URL, published bool) string {
+	language, path := i18n.Split(target.Path)
+	snapshot := r.snapshot.Language(language)
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	switch {
+	case segments[0] == "blog" && len(segments) > 1:
+		segments = segments[1:]
+
+		if last := segments[len(segments)-1]; last == "feed.xml" && len(segments) > 1 {
+			segments = segments[:len(segments)-1]
+		} else if len(segments) > 1 {
+			if linked, ok := snapshot.Get(strings.Join(segments[:len(segments)-1], "/"), last); ok {
+				if published && !linked.Data.Published(time.Now()) {
+					return fmt.Sprintf("link %q to an unpublished article", link)
+				}
+
+				if target.Fragment != "" && !anchors(string(linked.Content))[target.Fragment] {
+					return fmt.Sprintf("no heading or element with id %q in %s", target.Fragment, linked.Source)
+				}
+
+				return ""
+			}
+		}
+
+		if !snapshot.HasCategory(strings.Join(segments, "/")) {
+			return fmt.Sprintf("no article or category for %q", link)
+		}
+	case segments[0] == "tags" && len(segments) == 2:
+		if len(snapshot.ByTag(segments[1])) == 0 {
+			return fmt.Sprintf("no articles tagged %q for %q", segments[1], link)
+		}
+	case segments[0] == "series" && len(segments) == 2:
+		if _, ok := snapshot.Series(articles.SeriesSlug(segments[1])); !ok {
+			return fmt.Sprintf("no series %q for %q", segments[1], link)
+		}
+	}
+
+	return ""
+}
+
+// resolveStatic returns what is wrong with the path of a static file, or ""
+// when it exists.
+func (r *resolver) resolveStatic(path string) string {
+	relative, ok := strings.CutPrefix(path, "/static/")
+	if !ok {
+		return fmt.Sprintf("%q isn't under /static", path)
+	}
+
+	if r.static == nil {
+		return ""
+	}
+
+	if info, err := fs.Stat(r.static, relative); err != nil || info.IsDir() {
+		return fmt.Sprintf("%q doesn't exist under %s", path, r.staticDir)
+	}
+
+	return ""
+}
@@ -0,0 +1,189 @@
+package check
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"coding-kittens.com/modules/articles"
+)
+
+var testRoutes = []string{
+	"/",
+	"/blog",
+	"/blog/*path",
+	"/tags",
+	"/tags/:tag",
+	"/series/:name",
+	"/es/blog/*path",
+	"/es/tags/:tag",
+	"/image",
+}
+
+func article(frontMatter string, body string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte("---\n" + frontMatter + "\n---\n" + body)}
+}
+
+var testArticles = fstest.MapFS{
+	"css/navbar.mdx": article(
+		"title: Navbar\nthumbnail: /static/assets/thumbnails/navbar.jpeg\ntags: [CSS]\nseries: Modern CSS\ncreatedAt: 2024-01-20",
+		"## Less fun implementation\n\nText.",
+	),
+	"css/navbar.es.mdx": article(
+		"title: Barra\nthumbnail: /static/assets/thumbnails/navbar.jpeg\ncreatedAt: 2024-01-20",
+		"## Ejemplo\n\nTexto.",
+	),
+	"css/draft.mdx": article(
+		"title: Draft\nthumbnail: /static/assets/thumbnails/navbar.jpeg\ndraft: true",
+		"Soon.",
+	),
+}
+
+var testStatic = fstest.MapFS{
+	"assets/thumbnails/navbar.jpeg": {Data: []byte("jpeg")},
+}
+
+func TestMatchRoute(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{pattern: "/", path: "/", want: true},
+		{pattern: "/blog", path: "/blog", want: true},
+		{pattern: "/blog", path: "/blog/", want: true},
+		{pattern: "/blog", path: "/blog/css", want: false},
+		{pattern: "/blog/*path", path: "/blog/css/navbar", want: true},
+		{pattern: "/tags/:tag", path: "/tags/css", want: true},
+		{pattern: "/tags/:tag", path: "/tags", want: false},
+		{pattern: "/tags/:tag", path: "/tags/css/more", want: false},
+		{pattern: "/tags", path: "/series", want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pattern+" "+test.path, func(t *testing.T) {
+			if got := matchRoute(test.pattern, test.path); got != test.want {
+				t.Errorf("matchRoute(%q, %q) = %v, want %v", test.pattern, test.path, got, test.want)
+			}
+		})
+	}
+}
+
+func TestLinks(t *testing.T) {
+	content := `<a href="/blog?a=1&amp;b=2">x</a> <img src="/static/a.png" srcset="/image?w=1 1x, /image?w=2 2x"> <video poster="/static/p.png"></video> <a data-href="/ignored">`
+	want := []string{"/blog?a=1&b=2", "/static/a.png", "/static/p.png", "/image?w=1", "/image?w=2"}
+
+	if got := links(content); !reflect.DeepEqual(got, want) {
+		t.Errorf("links() = %q, want %q", got, want)
+	}
+
+	if got := anchors(`<h2 id="a&amp;b"></h2> <div id="c"></div>`); !reflect.DeepEqual(got, map[string]bool{"a&b": true, "c": true}) {
+		t.Errorf("anchors() = %v", got)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	snapshot := articles.NewRepository(testArticles, articles.Options{ShowUnpublished: true}).Rebuild()
+	r := &resolver{snapshot: snapshot, static: testStatic, staticDir: STATIC_DIR, routes: testRoutes}
+	ids := map[string]bool{"intro": true}
+
+	tests := []struct {
+		link      string
+		published bool
+		want      string // part of the problem, "" when the link resolves
+	}{
+		{link: "https://example.com/anything"},
+		{link: "mailto:someone@example.com"},
+		{link: "#intro"},
+		{link: "#outro", want: `no heading or element with id "outro"`},
+		{link: "blog/css", want: "relative link"},
+		{link: "/static/assets/thumbnails/navbar.jpeg"},
+		{link: "/static/assets/missing.png", want: "doesn't exist under"},
+		{link: "/image?url=/static/assets/thumbnails/navbar.jpeg&w=300"},
+		{link: "/image?url=/static/assets/missing.png&w=300", want: "image "},
+		{link: "/image?url=/elsewhere.png", want: "isn't under /static"},
+		{link: "/nowhere", want: "no route"},
+		{link: "/blog"},
+		{link: "/blog/css"},
+		{link: "/blog/html", want: "no article or category"},
+		{link: "/blog/css/navbar"},
+		{link: "/blog/css/navbar#less-fun-implementation"},
+		{link: "/blog/css/navbar#missing", want: `no heading or element with id "missing" in web/_articles/css/navbar.mdx`},
+		{link: "/blog/css/missing", want: "no article or category"},
+		{link: "/blog/css/feed.xml"},
+		{link: "/blog/css/draft"},
+		{link: "/blog/css/draft", published: true, want: "unpublished article"},
+		{link: "/es/blog/css/navbar#ejemplo"},
+		{link: "/es/blog/css/draft", want: "no article or category"},
+		{link: "/tags/css"},
+		{link: "/tags/html", want: "no articles tagged"},
+		{link: "/es/tags/css", want: "no articles tagged"},
+		{link: "/series/modern-css"},
+		{link: "/series/old-css", want: "no series"},
+		{link: "/%zz", want: "malformed link"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.link, func(t *testing.T) {
+			got := r.resolve(test.link, "en", ids, test.published)
+
+			if (test.want == "" && got != "") || !strings.Contains(got, test.want) {
+				t.Errorf("resolve(%q) = %q, want %q", test.link, got, test.want)
+			}
+		})
+	}
+}
+
+func TestContent(t *testing.T) {
+	fsys := fstest.MapFS{
+		"css/navbar.mdx": testArticles["css/navbar.mdx"],
+		"css/links.mdx": article(
+			"title: Links\nthumbnail: /static/assets/thumbnails/missing.jpeg\ncreatedAt: 2024-01-20",
+			"See [the navbar](/blog/css/navbar#less-fun-implementation) and [this](#nowhere).",
+		),
+		// Articles that fail to render still get their links checked
+		"css/broken.mdx": article(
+			"title: Broken\nthumbnail: /static/assets/thumbnails/navbar.jpeg\ncreatedAt: 2024-01-20",
+			"<Unknown />\n\n## Heading\n\n[up](#heading) [gone](/blog/css/gone)",
+		),
+	}
+
+	tests := []struct {
+		dir  string
+		want []string
+	}{
+		{
+			dir: "",
+			want: []string{
+				"web/_articles/css/broken.mdx:6: unknown MDX component <Unknown>",
+				`web/_articles/css/broken.mdx:10: no article or category for "/blog/css/gone"`,
+				`web/_articles/css/links.mdx:3: thumbnail "/static/assets/thumbnails/missing.jpeg" doesn't exist under web/static`,
+				`web/_articles/css/links.mdx:6: no heading or element with id "nowhere" for "#nowhere"`,
+			},
+		},
+		{
+			// Problems point at the content directory in use
+			dir: "/tmp/content",
+			want: []string{
+				"/tmp/content/_articles/css/broken.mdx:6: unknown MDX component <Unknown>",
+				`/tmp/content/_articles/css/broken.mdx:10: no article or category for "/blog/css/gone"`,
+				`/tmp/content/_articles/css/links.mdx:3: thumbnail "/static/assets/thumbnails/missing.jpeg" doesn't exist under /tmp/content/static`,
+				`/tmp/content/_articles/css/links.mdx:6: no heading or element with id "nowhere" for "#nowhere"`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.dir, func(t *testing.T) {
+			var got []string
+			for _, diagnostic := range Content(test.dir, fsys, testStatic, testRoutes) {
+				got = append(got, diagnostic.String())
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Content() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(test.want, "\n"))
+			}
+		})
+	}
+}
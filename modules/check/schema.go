@@ -0,0 +1,167 @@
+package check
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Kinds of front matter values.
+const (
+	STRING  = "string"
+	STRINGS = "list of strings"
+	DATE    = "date"
+	BOOL    = "boolean"
+	INT     = "integer"
+)
+
+type field struct {
+	Kind     string
+	Required bool
+}
+
+// SCHEMA lists every field an article front matter may have.
+var SCHEMA = map[string]field{
+	"title":            {Kind: STRING, Required: true},
+	"subtitle":         {Kind: STRING},
+	"thumbnail":        {Kind: STRING, Required: true},
+	"shortDescription": {Kind: STRING},
+	"createdAt":        {Kind: DATE},
+	"updatedAt":        {Kind: DATE},
+	"publishAt":        {Kind: DATE},
+	"tags":             {Kind: STRINGS},
+	"draft":            {Kind: BOOL},
+	"author":           {Kind: STRING},
+	"series":           {Kind: STRING},
+	"seriesOrder":      {Kind: INT},
+	"slug":             {Kind: STRING},
+	"description":      {Kind: STRING},
+	"canonical":        {Kind: STRING},
+	"robots":           {Kind: STRING},
+	"ogType":           {Kind: STRING},
+}
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+var yamlLinePattern = regexp.MustCompile(`^yaml: line (\d+): `)
+
+// kindOf returns the kind of a front matter value.
+func kindOf(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		switch node.ShortTag() {
+		case "!!str":
+			return STRING
+		case "!!timestamp":
+			return DATE
+		case "!!bool":
+			return BOOL
+		case "!!int":
+			return INT
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			if kindOf(item) != STRING {
+				return "list"
+			}
+		}
+
+		return STRINGS
+	case yaml.MappingNode:
+		return "mapping"
+	}
+
+	return node.ShortTag()
+}
+
+// checkFrontMatter validates the front matter of the source against SCHEMA.
+func (s *source) checkFrontMatter() []Diagnostic {
+	if !s.hasFrontMatter() {
+		return []Diagnostic{s.diagnostic(1, "missing front matter")}
+	}
+
+	var document yaml.Node
+
+	if err := yaml.Unmarshal(s.frontMatter, &document); err != nil {
+		line := 2
+		message := strings.TrimPrefix(err.Error(), "yaml: ")
+
+		// Lines of the YAML errors count from the first front matter line
+		if match := yamlLinePattern.FindStringSubmatch(err.Error()); match != nil {
+			if yamlLine, err := strconv.Atoi(match[1]); err == nil {
+				line = yamlLine + 1
+			}
+
+			message = strings.TrimPrefix(err.Error(), match[0])
+		}
+
+		return []Diagnostic{s.diagnostic(line, "invalid front matter: "+message)}
+	}
+
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return []Diagnostic{s.diagnostic(2, "front matter must be a mapping of fields")}
+	}
+
+	var diagnostics []Diagnostic
+
+	values := map[string]*yaml.Node{}
+	mapping := document.Content[0]
+
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := mapping.Content[i], mapping.Content[i+1]
+		line := key.Line + 1
+
+		s.keyLines[key.Value] = line
+		values[key.Value] = value
+
+		expected, ok := SCHEMA[key.Value]
+		if !ok {
+			diagnostics = append(diagnostics, s.diagnostic(line, fmt.Sprintf("unknown front matter field %q", key.Value)))
+			continue
+		}
+
+		if kind := kindOf(value); kind != expected.Kind {
+			diagnostics = append(diagnostics, s.diagnostic(line, fmt.Sprintf("%s: expected %s, found %s", key.Value, expected.Kind, kind)))
+		}
+	}
+
+	var missing []string
+	for name, expected := range SCHEMA {
+		if _, ok := values[name]; expected.Required && !ok {
+			missing = append(missing, name)
+		}
+	}
+
+	sort.Strings(missing)
+
+	for _, name := range missing {
+		diagnostics = append(diagnostics, s.diagnostic(2, "missing required front matter field "+name))
+	}
+
+	if values["createdAt"] == nil && values["publishAt"] == nil {
+		diagnostics = append(diagnostics, s.diagnostic(2, "front matter needs a createdAt or a publishAt date"))
+	}
+
+	if slug := values["slug"]; slug != nil && !slugPattern.MatchString(slug.Value) {
+		diagnostics = append(diagnostics, s.diagnostic(s.keyLines["slug"], fmt.Sprintf("slug %q must be lowercase words separated by dashes", slug.Value)))
+	}
+
+	if values["seriesOrder"] != nil && values["series"] == nil {
+		diagnostics = append(diagnostics, s.diagnostic(s.keyLines["seriesOrder"], "seriesOrder without a series"))
+	}
+
+	if created, updated := values["createdAt"], values["updatedAt"]; created != nil && updated != nil {
+		var createdAt, updatedAt time.Time
+
+		if created.Decode(&createdAt) == nil && updated.Decode(&updatedAt) == nil && updatedAt.Before(createdAt) {
+			diagnostics = append(diagnostics, s.diagnostic(s.keyLines["updatedAt"], "updatedAt is before createdAt"))
+		}
+	}
+
+	return diagnostics
+}
@@ -0,0 +1,62 @@
+package check
+
+import (
+	"strings"
+)
+
+const FRONT_MATTER_DELIMITER = "---"
+
+// source is the raw text of an article file, used to point diagnostics at
+// the line they are about.
+type source struct {
+	path        string
+	lines       []string
+	frontMatter []byte
+	bodyLine    int            // first line after the front matter
+	keyLines    map[string]int // line of every front matter field
+}
+
+func newSource(path string, file []byte) *source {
+	src := &source{
+		path:     path,
+		lines:    strings.Split(string(file), "\n"),
+		bodyLine: 1,
+		keyLines: map[string]int{},
+	}
+
+	if len(src.lines) == 0 || strings.TrimSpace(src.lines[0]) != FRONT_MATTER_DELIMITER {
+		return src
+	}
+
+	for i := 1; i < len(src.lines); i++ {
+		if strings.TrimSpace(src.lines[i]) == FRONT_MATTER_DELIMITER {
+			src.frontMatter = []byte(strings.Join(src.lines[1:i], "\n"))
+			src.bodyLine = i + 2
+			break
+		}
+	}
+
+	return src
+}
+
+// hasFrontMatter reports whether the file starts with a closed front matter.
+func (s *source) hasFrontMatter() bool {
+	return s.bodyLine > 1
+}
+
+// lineOf returns the first line of the body containing text, or the first
+// line of the body when none does.
+func (s *source) lineOf(text string) int {
+	for i := s.bodyLine - 1; i < len(s.lines); i++ {
+		if strings.Contains(s.lines[i], text) {
+			return i + 1
+		}
+	}
+
+	return s.bodyLine
+}
+
+// diagnostic returns a problem found at line of the source.
+func (s *source) diagnostic(line int, message string) Diagnostic {
+	return Diagnostic{Path: s.path, Line: line, Message: message}
+}
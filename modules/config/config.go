@@ -0,0 +1,112 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// Config holds the site settings read from the environment.
+type Config struct {
+	SiteURL         string // absolute URL the site is served from, without trailing slash
+	SiteName        string
+	SiteDescription string
+	Author          string
+	AuthorImage     string   // path of the picture of the author
+	Profiles        []string // URLs of the author elsewhere, e.g. GitHub
+	Environment     string   // "production" lets crawlers in, anything else keeps them out
+	RobotsDisallow  []string // paths crawlers shouldn't visit in production
+	PreviewSecret   string   // key signing the preview links of unpublished articles
+	ContentDir      string   // directory of the articles, templates and static files, "" for the embedded copy
+}
+
+const PRODUCTION = "production"
+
+var current = defaults("http://localhost:8080", "development")
+
+func defaults(siteURL string, environment string) Config {
+	return Config{
+		SiteURL:         siteURL,
+		SiteName:        "Coding Kittens",
+		SiteDescription: "Curiosity Didn't Kill The Cat",
+		Author:          "Javier Muñoz Tous",
+		AuthorImage:     "/static/assets/me.jpeg",
+		Environment:     environment,
+		RobotsDisallow:  []string{"/search"},
+		Profiles: []string{
+			"https://twitter.com/Javimt_ib",
+			"https://github.com/Javimtib92",
+			"https://www.linkedin.com/in/javier-muñoz-tous/",
+			"https://www.instagram.com/javimtib92",
+		},
+	}
+}
+
+func env(key string, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+
+	return fallback
+}
+
+// list splits a comma separated variable, "-" standing for an empty list.
+func list(key string, fallback []string) []string {
+	value := env(key, "")
+
+	switch value {
+	case "":
+		return fallback
+	case "-":
+		return nil
+	}
+
+	var items []string
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// Load reads the configuration from the environment, falling back to
+// defaultURL and defaultEnvironment when SITE_URL and ENVIRONMENT aren't set.
+func Load(defaultURL string, defaultEnvironment string) Config {
+	config := defaults(defaultURL, defaultEnvironment)
+
+	config.SiteURL = strings.TrimSuffix(env("SITE_URL", config.SiteURL), "/")
+	config.SiteName = env("SITE_NAME", config.SiteName)
+	config.SiteDescription = env("SITE_DESCRIPTION", config.SiteDescription)
+	config.Author = env("SITE_AUTHOR", config.Author)
+	config.AuthorImage = env("SITE_AUTHOR_IMAGE", config.AuthorImage)
+	config.Profiles = list("SITE_PROFILES", config.Profiles)
+	config.Environment = env("ENVIRONMENT", config.Environment)
+	config.RobotsDisallow = list("ROBOTS_DISALLOW", config.RobotsDisallow)
+	config.PreviewSecret = env("PREVIEW_SECRET", config.PreviewSecret)
+	config.ContentDir = env("CONTENT_DIR", config.ContentDir)
+
+	current = config
+
+	return config
+}
+
+// Get returns the loaded configuration.
+func Get() Config {
+	return current
+}
+
+// Production reports whether the site runs in production.
+func (c Config) Production() bool {
+	return c.Environment == PRODUCTION
+}
+
+// AbsoluteURL turns a site path such as /blog into an absolute URL.
+func (c Config) AbsoluteURL(path string) string {
+	if strings.Contains(path, "://") {
+		return path
+	}
+
+	return c.SiteURL + "/" + strings.TrimPrefix(path, "/")
+}
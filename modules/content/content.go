@@ -0,0 +1,147 @@
+package content
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sync/atomic"
+)
+
+// Directories of a content root, laid out like the web directory of the
+// repository.
+const (
+	ARTICLES_DIR  = "_articles"
+	TEMPLATES_DIR = "templates"
+	STATIC_DIR    = "static"
+	LOCALES_DIR   = "locales"
+	EXAMPLES_DIR  = "_examples"
+)
+
+// ARTICLE_EXTENSION is the extension of the article files.
+const ARTICLE_EXTENSION = ".mdx"
+
+// Content is a copy of the articles, templates, static files, message
+// catalogues and code examples the site is built from.
+type Content struct {
+	Dir       string // directory it was read from, "" for the embedded copy
+	Articles  fs.FS
+	Templates fs.FS
+	Static    fs.FS
+	Locales   fs.FS
+	Examples  fs.FS
+}
+
+// Embedded returns the copy compiled into the binary, root being laid out
+// like the web directory.
+func Embedded(root fs.FS) Content {
+	return fromRoot(root, "")
+}
+
+// Open returns the copy under dir, which must hold the articles, templates
+// and static directories. The message catalogues and the examples are
+// optional and come from fallback when dir has none.
+func Open(dir string, fallback Content) (Content, error) {
+	root := os.DirFS(dir)
+
+	for _, part := range []string{ARTICLES_DIR, TEMPLATES_DIR, STATIC_DIR} {
+		info, err := fs.Stat(root, part)
+		if err != nil {
+			return Content{}, err
+		}
+
+		if !info.IsDir() {
+			return Content{}, fmt.Errorf("%s is not a directory", path.Join(dir, part))
+		}
+	}
+
+	content := fromRoot(root, dir)
+
+	if _, err := fs.Stat(root, LOCALES_DIR); errors.Is(err, fs.ErrNotExist) {
+		content.Locales = fallback.Locales
+	}
+
+	if _, err := fs.Stat(root, EXAMPLES_DIR); errors.Is(err, fs.ErrNotExist) {
+		content.Examples = fallback.Examples
+	}
+
+	// An empty volume would take every article down
+	if !hasArticles(content.Articles) {
+		return Content{}, fmt.Errorf("no %s files under %s", ARTICLE_EXTENSION, path.Join(dir, ARTICLES_DIR))
+	}
+
+	return content, nil
+}
+
+func fromRoot(root fs.FS, dir string) Content {
+	sub := func(part string) fs.FS {
+		fsys, _ := fs.Sub(root, part)
+		return fsys
+	}
+
+	return Content{
+		Dir:       dir,
+		Articles:  sub(ARTICLES_DIR),
+		Templates: sub(TEMPLATES_DIR),
+		Static:    sub(STATIC_DIR),
+		Locales:   sub(LOCALES_DIR),
+		Examples:  sub(EXAMPLES_DIR),
+	}
+}
+
+func hasArticles(fsys fs.FS) bool {
+	found := errors.New("found")
+
+	err := fs.WalkDir(fsys, ".", func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !entry.IsDir() && path.Ext(filePath) == ARTICLE_EXTENSION {
+			return found
+		}
+
+		return nil
+	})
+
+	return err == found
+}
+
+var current atomic.Pointer[Content]
+
+// Current returns the copy the site is being served from.
+func Current() Content {
+	if content := current.Load(); content != nil {
+		return *content
+	}
+
+	return Content{}
+}
+
+// Swap makes content the copy the site is served from.
+func Swap(content Content) {
+	current.Store(&content)
+}
+
+// view is a file system reading from a part of the current copy, so its
+// users see the new files as soon as a reloaded copy is swapped in.
+type view func(Content) fs.FS
+
+func (v view) Open(name string) (fs.File, error) {
+	fsys := v(Current())
+	if fsys == nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return fsys.Open(name)
+}
+
+// Views of the parts of the current copy.
+var (
+	Articles  fs.FS = view(func(c Content) fs.FS { return c.Articles })
+	Templates fs.FS = view(func(c Content) fs.FS { return c.Templates })
+	Static    fs.FS = view(func(c Content) fs.FS { return c.Static })
+	Locales   fs.FS = view(func(c Content) fs.FS { return c.Locales })
+	Examples  fs.FS = view(func(c Content) fs.FS { return c.Examples })
+)
@@ -0,0 +1,176 @@
+package content
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Source serves the site from a directory, falling back to the embedded
+// copy until the directory has loaded once.
+type Source struct {
+	dir      string
+	embedded Content
+	prepare  func(Content) (func(), error) // builds what is served from a copy, failing when it's invalid
+	loaded   bool                          // whether a copy of the directory has been served
+	mutex    sync.Mutex
+}
+
+// NewSource serves the site from dir, or from embedded when dir is "".
+// prepare builds what is served from a copy without publishing it, and
+// rejects the copy by returning an error. The function it returns publishes
+// what it built and is called as the copy is swapped in.
+func NewSource(dir string, embedded Content, prepare func(Content) (func(), error)) *Source {
+	return &Source{dir: dir, embedded: embedded, prepare: prepare}
+}
+
+// Reload reads the directory again and swaps it in. When it can't be used,
+// the last copy of the directory keeps being served, or the embedded copy
+// when none has loaded yet, and the error is returned.
+func (s *Source) Reload() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.dir != "" {
+		loaded, err := Open(s.dir, s.embedded)
+
+		var publish func()
+		if err == nil {
+			publish, err = s.prepare(loaded)
+		}
+
+		if err == nil {
+			s.loaded = true
+			swap(loaded, publish)
+
+			return nil
+		}
+
+		// Live edits aren't thrown away for one broken file
+		if s.loaded {
+			log.Printf("Content of %s can't be used, keeping the current copy: %v", s.dir, err)
+			return err
+		}
+
+		log.Printf("Content of %s can't be used, serving the embedded copy: %v", s.dir, err)
+
+		if err := s.useEmbedded(); err != nil {
+			log.Printf("Embedded content can't be used: %v", err)
+		}
+
+		return err
+	}
+
+	return s.useEmbedded()
+}
+
+func (s *Source) useEmbedded() error {
+	publish, err := s.prepare(s.embedded)
+	swap(s.embedded, publish)
+
+	return err
+}
+
+func swap(content Content, publish func()) {
+	Swap(content)
+
+	if publish != nil {
+		publish()
+	}
+}
+
+const debounceDelay = 150 * time.Millisecond
+
+// Watch reloads the directory whenever a file under it changes, until ctx
+// is cancelled. While the directory is missing, its parent is watched for
+// it to appear.
+func (s *Source) Watch(ctx context.Context) {
+	if s.dir == "" {
+		return
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Println("Error watching content:", err)
+		return
+	}
+	defer watcher.Close()
+
+	dir := filepath.Clean(s.dir)
+
+	if err := watcher.Add(filepath.Dir(dir)); err != nil {
+		log.Println("Error watching dir:", err)
+	}
+
+	addDirectories(watcher, dir)
+
+	var debounceTimer *time.Timer
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+
+			// Siblings of the directory don't matter
+			if event.Name != dir && !strings.HasPrefix(event.Name, dir+string(filepath.Separator)) {
+				continue
+			}
+
+			if event.Op&fsnotify.Create == fsnotify.Create && !strings.HasPrefix(filepath.Base(event.Name), ".") {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					addDirectories(watcher, event.Name)
+				}
+			}
+
+			if debounceTimer != nil {
+				debounceTimer.Stop()
+			}
+
+			debounceTimer = time.AfterFunc(debounceDelay, func() {
+				if err := s.Reload(); err == nil {
+					log.Printf("Content reloaded from %s", s.dir)
+				}
+			})
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Println("Error watching content:", err)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func addDirectories(watcher *fsnotify.Watcher, dir string) {
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !entry.IsDir() {
+			return nil
+		}
+
+		// Dot directories such as .git hold nothing the site is built from
+		if path != dir && strings.HasPrefix(entry.Name(), ".") {
+			return filepath.SkipDir
+		}
+
+		return watcher.Add(path)
+	})
+
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Println("Error watching dir:", err)
+	}
+}
@@ -0,0 +1,174 @@
+package content
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"testing/fstest"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// writeFiles creates files, by path relative to dir, with their content.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, data := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+var testEmbedded = Embedded(fstest.MapFS{
+	"_articles/css/embedded.mdx": {Data: []byte("Embedded")},
+	"templates/root.tmpl":        {Data: []byte("root")},
+	"static/css/styles.css":      {Data: []byte("body {}")},
+	"locales/en.json":            {Data: []byte("{}")},
+})
+
+// testSource serves dir, rejecting the copies with a broken article, and
+// counts the copies published.
+func testSource(dir string) (*Source, *[]string) {
+	var published []string
+
+	source := NewSource(dir, testEmbedded, func(c Content) (func(), error) {
+		if _, err := fs.Stat(c.Articles, "css/broken.mdx"); err == nil {
+			return nil, errors.New("broken article")
+		}
+
+		return func() { published = append(published, c.Dir) }, nil
+	})
+
+	return source, &published
+}
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := Open(dir, testEmbedded); err == nil {
+		t.Error("Open() of an empty directory succeeded")
+	}
+
+	writeFiles(t, dir, map[string]string{
+		"_articles/css/notes.txt": "Not an article",
+		"templates/root.tmpl":     "root",
+		"static/css/styles.css":   "body {}",
+	})
+
+	if _, err := Open(dir, testEmbedded); err == nil {
+		t.Error("Open() of a directory without articles succeeded")
+	}
+
+	writeFiles(t, dir, map[string]string{"_articles/css/navbar.mdx": "Navbar"})
+
+	content, err := Open(dir, testEmbedded)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	if _, err := fs.Stat(content.Articles, "css/navbar.mdx"); err != nil || content.Dir != dir {
+		t.Errorf("Open() = %+v, want the articles of the directory", content)
+	}
+
+	// The message catalogues come from the fallback when the directory has none
+	if _, err := fs.Stat(content.Locales, "en.json"); err != nil {
+		t.Errorf("Open() locales = %v, want those of the fallback", err)
+	}
+}
+
+func TestReload(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "content")
+	source, published := testSource(dir)
+
+	// The embedded copy is served until the directory exists
+	if err := source.Reload(); err == nil || Current().Dir != "" {
+		t.Fatalf("Reload() = %v, serving %q, want the embedded copy", err, Current().Dir)
+	}
+
+	writeFiles(t, dir, map[string]string{
+		"_articles/css/navbar.mdx": "Navbar",
+		"templates/root.tmpl":      "root",
+		"static/css/styles.css":    "body {}",
+	})
+
+	if err := source.Reload(); err != nil || Current().Dir != dir {
+		t.Fatalf("Reload() = %v, serving %q, want %q", err, Current().Dir, dir)
+	}
+
+	if _, err := fs.Stat(Articles, "css/navbar.mdx"); err != nil {
+		t.Errorf("Articles = %v, want the view to read the swapped copy", err)
+	}
+
+	// A broken edit keeps the last good copy instead of the embedded one
+	writeFiles(t, dir, map[string]string{"_articles/css/broken.mdx": "Broken"})
+
+	if err := source.Reload(); err == nil || Current().Dir != dir {
+		t.Fatalf("Reload() = %v, serving %q, want %q kept", err, Current().Dir, dir)
+	}
+
+	if want := []string{"", dir}; !reflect.DeepEqual(*published, want) {
+		t.Errorf("published %q, want %q", *published, want)
+	}
+}
+
+func TestReloadBrokenAtStart(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"_articles/css/broken.mdx": "Broken",
+		"templates/root.tmpl":      "root",
+		"static/css/styles.css":    "body {}",
+	})
+
+	source, published := testSource(dir)
+
+	if err := source.Reload(); err == nil || Current().Dir != "" || len(*published) != 1 {
+		t.Errorf("Reload() = %v, serving %q, published %q, want the embedded copy", err, Current().Dir, *published)
+	}
+
+	source, _ = testSource("")
+
+	if err := source.Reload(); err != nil || Current().Dir != "" {
+		t.Errorf("Reload() = %v, serving %q, want the embedded copy", err, Current().Dir)
+	}
+}
+
+func TestAddDirectories(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"_articles/css/navbar.mdx": "Navbar",
+		".git/objects/ab/cdef":     "object",
+		"templates/.cache/x":       "cache",
+	})
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Close()
+
+	addDirectories(watcher, dir)
+
+	var watched []string
+	for _, path := range watcher.WatchList() {
+		relative, _ := filepath.Rel(dir, path)
+		watched = append(watched, filepath.ToSlash(relative))
+	}
+
+	sort.Strings(watched)
+
+	// Dot directories such as .git are left out
+	if want := []string{".", "_articles", "_articles/css", "templates"}; !reflect.DeepEqual(watched, want) {
+		t.Errorf("watched %q, want %q", watched, want)
+	}
+}
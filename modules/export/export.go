@@ -0,0 +1,380 @@
+package export
+
+import (
+	"html"
+	"io/fs"
+	"log"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"coding-kittens.com/modules/i18n"
+)
+
+// IMAGE_FORMAT is the format the resized images are exported in, since
+// static hosting can't negotiate it with the Accept header.
+const IMAGE_FORMAT = "image/webp"
+
+// VARIANTS_DIR is where the resized images end up, under /static.
+const VARIANTS_DIR = "/static/variants"
+
+var (
+	attributePattern = regexp.MustCompile(`\s(href|src|srcset|hx-get|poster|content|action)="([^"]*)"`)
+	locPattern       = regexp.MustCompile(`<loc>([^<]+)</loc>`)
+)
+
+// page is a response of the site saved to a file.
+type page struct {
+	file        string
+	body        []byte
+	contentType string
+}
+
+// exporter crawls a site through its handler, without a server.
+type exporter struct {
+	handler  http.Handler
+	siteURL  string
+	excluded map[string]bool
+	queue    []string
+	seen     map[string]bool
+	pages    []page
+
+	// Exported URLs of the links that can't stay as they are: pages with a
+	// query string, their htmx fragments and the resized images
+	rewrites map[string]string
+	partials map[string]string
+}
+
+// Site renders every page reachable from seeds through handler, the same way
+// the server would, and writes it to out along with the files of static,
+// which are served under /static. Pages get pretty URLs, /blog/css turning
+// into blog/css/index.html, and the /image variants they use are exported
+// as files. siteURL is the prefix of the absolute URLs of the site.
+// excluded are the paths of the pages static hosting can't serve, e.g.
+// /search, which are left out in every language along with their fragments.
+func Site(handler http.Handler, static fs.FS, siteURL string, seeds []string, excluded []string, out string) (int, error) {
+	e := &exporter{
+		handler:  handler,
+		siteURL:  strings.TrimSuffix(siteURL, "/"),
+		excluded: map[string]bool{},
+		seen:     map[string]bool{},
+		rewrites: map[string]string{},
+		partials: map[string]string{},
+	}
+
+	for _, excludedPath := range excluded {
+		e.excluded[excludedPath] = true
+	}
+
+	for _, seed := range seeds {
+		e.enqueue(seed)
+	}
+
+	for len(e.queue) > 0 {
+		next := e.queue[0]
+		e.queue = e.queue[1:]
+
+		e.visit(next)
+	}
+
+	for _, p := range e.pages {
+		body := p.body
+
+		if strings.HasPrefix(p.contentType, "text/html") {
+			body = e.rewriteHTML(body)
+		} else {
+			body = e.rewriteText(body)
+		}
+
+		if err := write(out, p.file, body); err != nil {
+			return 0, err
+		}
+	}
+
+	err := fs.WalkDir(static, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			return err
+		}
+
+		body, err := fs.ReadFile(static, name)
+		if err != nil {
+			return err
+		}
+
+		return write(out, path.Join("/static", name), body)
+	})
+
+	return len(e.pages), err
+}
+
+func write(out string, file string, body []byte) error {
+	target := filepath.Join(out, filepath.FromSlash(file))
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(target, body, 0o644)
+}
+
+// internal returns the path and query of link when it points at the site.
+func (e *exporter) internal(link string) (string, bool) {
+	if rest, ok := strings.CutPrefix(link, e.siteURL); ok && e.siteURL != "" {
+		link = rest
+		if link == "" {
+			link = "/"
+		}
+	}
+
+	if !strings.HasPrefix(link, "/") || strings.HasPrefix(link, "//") {
+		return "", false
+	}
+
+	link, _, _ = strings.Cut(link, "#")
+
+	// The language switch only sets a cookie, the page is the same
+	if target, err := url.Parse(link); err == nil && target.Query().Has(i18n.QUERY_PARAMETER) {
+		query := target.Query()
+		query.Del(i18n.QUERY_PARAMETER)
+		target.RawQuery = query.Encode()
+		link = target.String()
+	}
+
+	return link, true
+}
+
+// partial marks htmx fragment requests in the queue.
+const partial = "partial:"
+
+func (e *exporter) enqueue(link string) {
+	if e.seen[link] {
+		return
+	}
+
+	e.seen[link] = true
+	e.queue = append(e.queue, link)
+}
+
+// prettyPath folds the query string of a page into its path, e.g.
+// /blog?page=2 into /blog/page/2.
+func prettyPath(target *url.URL) string {
+	pretty := strings.TrimSuffix(target.Path, "/")
+
+	query := target.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, value := range query[key] {
+			pretty += "/" + url.PathEscape(key) + "/" + url.PathEscape(value)
+		}
+	}
+
+	return pretty
+}
+
+func (e *exporter) visit(link string) {
+	isPartial := strings.HasPrefix(link, partial)
+	link = strings.TrimPrefix(link, partial)
+
+	target, err := url.Parse(link)
+	if err != nil || strings.HasPrefix(target.Path, "/static/") {
+		return
+	}
+
+	if _, unlocalized := i18n.Split(target.Path); e.excluded[strings.TrimSuffix(unlocalized, "/")] {
+		return
+	}
+
+	request := httptest.NewRequest(http.MethodGet, link, nil)
+
+	if target.Path == "/image" {
+		request.Header.Set("Accept", IMAGE_FORMAT)
+	} else if isPartial {
+		request.Header.Set("HX-Request", "true")
+	}
+
+	recorder := httptest.NewRecorder()
+	e.handler.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	if location := response.Header.Get("Location"); response.StatusCode >= 300 && response.StatusCode < 400 {
+		if redirect, ok := e.internal(location); ok {
+			e.enqueue(redirect)
+		}
+
+		return
+	}
+
+	if response.StatusCode != http.StatusOK {
+		log.Printf("Skipping %s: %s", link, response.Status)
+		return
+	}
+
+	contentType := response.Header.Get("Content-Type")
+	body := recorder.Body.Bytes()
+
+	file := prettyPath(target)
+
+	switch {
+	case target.Path == "/image":
+		file = imageFile(target, contentType)
+		e.rewrites[link] = file
+	case isPartial:
+		file += "/partial.html"
+		e.partials[link] = file
+	case path.Ext(target.Path) != "" && target.RawQuery == "":
+		file = target.Path
+	default:
+		if target.RawQuery != "" {
+			e.rewrites[link] = file + "/"
+		}
+
+		file += "/index.html"
+	}
+
+	e.pages = append(e.pages, page{file: file, body: body, contentType: contentType})
+
+	if strings.HasPrefix(contentType, "text/html") {
+		e.discoverHTML(body)
+	} else if strings.Contains(contentType, "xml") {
+		for _, match := range locPattern.FindAllSubmatch(body, -1) {
+			if loc, ok := e.internal(html.UnescapeString(string(match[1]))); ok {
+				e.enqueue(loc)
+			}
+		}
+	}
+}
+
+// imageFile names the export of a resized image after the static file and
+// the parameters, e.g. /static/variants/assets/me-128.webp.
+func imageFile(target *url.URL, contentType string) string {
+	query := target.Query()
+	source := strings.TrimPrefix(query.Get("url"), "/static")
+	name := strings.TrimSuffix(source, path.Ext(source))
+
+	for _, key := range []string{"w", "h", "q"} {
+		if value := query.Get(key); value != "" {
+			if key == "w" {
+				name += "-" + value
+			} else {
+				name += "-" + key + value
+			}
+		}
+	}
+
+	extension := ".webp"
+	if extensions, _ := mime.ExtensionsByType(contentType); len(extensions) > 0 {
+		extension = extensions[0]
+	}
+
+	return VARIANTS_DIR + name + extension
+}
+
+// urls splits the value of an attribute into the URLs it holds, e.g. the
+// candidates of a srcset.
+func urls(attribute string, value string) []string {
+	if attribute != "srcset" {
+		return []string{value}
+	}
+
+	var candidates []string
+
+	for _, candidate := range strings.Split(value, ",") {
+		if fields := strings.Fields(candidate); len(fields) > 0 {
+			candidates = append(candidates, fields[0])
+		}
+	}
+
+	return candidates
+}
+
+func (e *exporter) discoverHTML(body []byte) {
+	for _, match := range attributePattern.FindAllSubmatch(body, -1) {
+		attribute := string(match[1])
+
+		for _, link := range urls(attribute, html.UnescapeString(string(match[2]))) {
+			link, ok := e.internal(link)
+			if !ok || attribute == "action" {
+				continue
+			}
+
+			if attribute == "hx-get" {
+				e.enqueue(partial + link)
+			} else {
+				e.enqueue(link)
+			}
+		}
+	}
+}
+
+// rewriteHTML points the links of a page at the exported files.
+func (e *exporter) rewriteHTML(body []byte) []byte {
+	return attributePattern.ReplaceAllFunc(body, func(match []byte) []byte {
+		groups := attributePattern.FindSubmatch(match)
+		attribute, value := string(groups[1]), html.UnescapeString(string(groups[2]))
+
+		rewrites := e.rewrites
+		if attribute == "hx-get" {
+			rewrites = e.partials
+		}
+
+		// Every candidate of a srcset on its own, the whole value otherwise
+		parts := []string{value}
+		if attribute == "srcset" {
+			parts = strings.Split(value, ",")
+		}
+
+		changed := false
+
+		for i, part := range parts {
+			fields := strings.Fields(part)
+			if len(fields) == 0 {
+				continue
+			}
+
+			if internal, ok := e.internal(fields[0]); ok {
+				if exported, ok := rewrites[internal]; ok {
+					parts[i] = strings.Replace(part, fields[0], exported, 1)
+					changed = true
+				}
+			}
+		}
+
+		if !changed {
+			return match
+		}
+
+		return []byte(string(match[:1]) + attribute + `="` + html.EscapeString(strings.Join(parts, ",")) + `"`)
+	})
+}
+
+// rewriteText points the resized images of feeds and other text files at
+// the exported files, in any of the ways their URLs may be escaped.
+func (e *exporter) rewriteText(body []byte) []byte {
+	text := string(body)
+
+	for link, exported := range e.rewrites {
+		if !strings.HasPrefix(link, "/image?") {
+			continue
+		}
+
+		for _, form := range []string{link, html.EscapeString(link), html.EscapeString(html.EscapeString(link)), strings.ReplaceAll(link, "&", `\u0026`)} {
+			text = regexp.MustCompile(regexp.QuoteMeta(form)+`(["'\s,<)]|$)`).ReplaceAllString(text, exported+"$1")
+		}
+	}
+
+	return []byte(text)
+}
@@ -0,0 +1,245 @@
+package export
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"testing/fstest"
+)
+
+func TestPrettyPath(t *testing.T) {
+	tests := []struct {
+		link string
+		want string
+	}{
+		{link: "/", want: ""},
+		{link: "/blog", want: "/blog"},
+		{link: "/blog/", want: "/blog"},
+		{link: "/blog?page=2", want: "/blog/page/2"},
+		{link: "/blog?page=2&category=css", want: "/blog/category/css/page/2"},
+		{link: "/es/blog?category=a%20b", want: "/es/blog/category/a%20b"},
+		{link: "/search?q=x&q=y", want: "/search/q/x/q/y"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.link, func(t *testing.T) {
+			target, err := url.Parse(test.link)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := prettyPath(target); got != test.want {
+				t.Errorf("prettyPath(%q) = %q, want %q", test.link, got, test.want)
+			}
+		})
+	}
+}
+
+func TestInternal(t *testing.T) {
+	e := &exporter{siteURL: "https://coding-kittens.com"}
+
+	tests := []struct {
+		link string
+		want string
+		ok   bool
+	}{
+		{link: "/blog", want: "/blog", ok: true},
+		{link: "/blog#top", want: "/blog", ok: true},
+		{link: "https://coding-kittens.com", want: "/", ok: true},
+		{link: "https://coding-kittens.com/blog/css", want: "/blog/css", ok: true},
+		{link: "/blog?lang=es", want: "/blog", ok: true},
+		{link: "/blog?lang=es&page=2", want: "/blog?page=2", ok: true},
+		{link: "https://example.com/blog", ok: false},
+		{link: "//example.com/blog", ok: false},
+		{link: "mailto:someone@example.com", ok: false},
+		{link: "#top", ok: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.link, func(t *testing.T) {
+			got, ok := e.internal(test.link)
+
+			if got != test.want || ok != test.ok {
+				t.Errorf("internal(%q) = %q, %v, want %q, %v", test.link, got, ok, test.want, test.ok)
+			}
+		})
+	}
+}
+
+func TestImageFile(t *testing.T) {
+	tests := []struct {
+		link        string
+		contentType string
+		want        string
+	}{
+		{link: "/image?url=/static/assets/me.png&w=128", contentType: "image/webp", want: "/static/variants/assets/me-128.webp"},
+		{link: "/image?url=/static/assets/me.png&w=128&h=64&q=80", contentType: "image/png", want: "/static/variants/assets/me-128-h64-q80.png"},
+		{link: "/image?url=/static/assets/me.png", contentType: "", want: "/static/variants/assets/me.webp"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.link, func(t *testing.T) {
+			target, err := url.Parse(test.link)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := imageFile(target, test.contentType); got != test.want {
+				t.Errorf("imageFile(%q) = %q, want %q", test.link, got, test.want)
+			}
+		})
+	}
+}
+
+func TestRewriteHTML(t *testing.T) {
+	e := &exporter{
+		rewrites: map[string]string{
+			"/blog?page=2":      "/blog/page/2/",
+			"/image?url=/a&w=1": "/static/variants/a-1.webp",
+			"/image?url=/a&w=2": "/static/variants/a-2.webp",
+		},
+		partials: map[string]string{
+			"/blog?page=2": "/blog/page/2/partial.html",
+		},
+	}
+
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "page with a query",
+			html: `<a href="/blog?page=2">`,
+			want: `<a href="/blog/page/2/">`,
+		},
+		{
+			name: "htmx fragment",
+			html: `<div hx-get="/blog?page=2">`,
+			want: `<div hx-get="/blog/page/2/partial.html">`,
+		},
+		{
+			name: "escaped image",
+			html: `<img src="/image?url=/a&amp;w=1">`,
+			want: `<img src="/static/variants/a-1.webp">`,
+		},
+		{
+			name: "srcset candidates",
+			html: `<img srcset="/image?url=/a&amp;w=1 1x, /image?url=/a&amp;w=2 2x">`,
+			want: `<img srcset="/static/variants/a-1.webp 1x, /static/variants/a-2.webp 2x">`,
+		},
+		{
+			name: "links left alone",
+			html: `<a href="/blog/css"> <a href="https://example.com/blog?page=2"> <form action="/search">`,
+			want: `<a href="/blog/css"> <a href="https://example.com/blog?page=2"> <form action="/search">`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := string(e.rewriteHTML([]byte(test.html))); got != test.want {
+				t.Errorf("rewriteHTML(%q) = %q, want %q", test.html, got, test.want)
+			}
+		})
+	}
+}
+
+func TestRewriteText(t *testing.T) {
+	e := &exporter{
+		rewrites: map[string]string{
+			"/image?url=/a&w=1": "/static/variants/a-1.webp",
+			"/blog?page=2":      "/blog/page/2/",
+		},
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "raw", text: `"/image?url=/a&w=1"`, want: `"/static/variants/a-1.webp"`},
+		{name: "escaped", text: `<img src="/image?url=/a&amp;w=1">`, want: `<img src="/static/variants/a-1.webp">`},
+		{name: "escaped twice", text: `&lt;img src="/image?url=/a&amp;amp;w=1"&gt;`, want: `&lt;img src="/static/variants/a-1.webp"&gt;`},
+		{name: "json", text: `{"image":"/image?url=/a&w=1"}`, want: `{"image":"/static/variants/a-1.webp"}`},
+		{name: "longer query", text: `"/image?url=/a&w=10"`, want: `"/image?url=/a&w=10"`},
+		{name: "pages left alone", text: `<link>/blog?page=2</link>`, want: `<link>/blog?page=2</link>`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := string(e.rewriteText([]byte(test.text))); got != test.want {
+				t.Errorf("rewriteText(%q) = %q, want %q", test.text, got, test.want)
+			}
+		})
+	}
+}
+
+func TestSite(t *testing.T) {
+	pages := map[string]string{
+		"/":                `<a href="/blog">Blog</a> <a href="/search">Search</a> <a href="/es/search?q=css">Buscar</a>`,
+		"/blog":            `<a href="/blog?page=2">Older</a>`,
+		"/blog?page=2":     `<a href="/blog">Newer</a> <div hx-get="/blog?page=3"></div>`,
+		"/blog?page=3":     `Last`,
+		"/search":          `Search`,
+		"/es/search?q=css": `Buscar`,
+	}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := pages[r.URL.RequestURI()]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(body))
+	})
+
+	out := t.TempDir()
+	static := fstest.MapFS{"css/styles.css": {Data: []byte("body {}")}}
+
+	count, err := Site(handler, static, "https://coding-kittens.com", []string{"/"}, []string{"/search"}, out)
+	if err != nil {
+		t.Fatalf("Site() error = %v", err)
+	}
+
+	var files []string
+	err = filepath.WalkDir(out, func(path string, entry os.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			relative, _ := filepath.Rel(out, path)
+			files = append(files, filepath.ToSlash(relative))
+		}
+
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(files)
+
+	want := []string{
+		"blog/index.html",
+		"blog/page/2/index.html",
+		"blog/page/3/partial.html",
+		"index.html",
+		"static/css/styles.css",
+	}
+
+	if !reflect.DeepEqual(files, want) || count != 4 {
+		t.Errorf("Site() = %d pages, %q, want 4 pages, %q", count, files, want)
+	}
+
+	page, err := os.ReadFile(filepath.Join(out, "blog", "page", "2", "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := string(page), `<a href="/blog">Newer</a> <div hx-get="/blog/page/3/partial.html"></div>`; got != want {
+		t.Errorf("blog/page/2/index.html = %q, want %q", got, want)
+	}
+}
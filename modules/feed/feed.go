@@ -0,0 +1,136 @@
+package feed
+
+import (
+	"io/fs"
+	"mime"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"coding-kittens.com/models"
+	"coding-kittens.com/modules/config"
+)
+
+// StaticAssets holds the static files, used to find the size of enclosures.
+var StaticAssets fs.FS
+
+// Feed is a format agnostic list of articles to syndicate.
+type Feed struct {
+	Title       string
+	Description string
+	Link        string // absolute URL of the HTML page the feed mirrors
+	Author      string
+	Language    string
+	Updated     time.Time
+	Items       []Item
+}
+
+// Item is a single entry of a feed. Every URL is absolute.
+type Item struct {
+	ID        string
+	Title     string
+	Link      string
+	Summary   string
+	Content   string // full HTML of the article
+	Image     *Enclosure
+	Tags      []string
+	Published time.Time
+	Updated   time.Time
+}
+
+// Enclosure is a media file attached to an item.
+type Enclosure struct {
+	URL    string
+	Type   string
+	Length int64
+}
+
+var (
+	urlAttributePattern    = regexp.MustCompile(`\b(src|href|poster)="(/[^/"][^"]*)"`)
+	srcsetAttributePattern = regexp.MustCompile(`\bsrcset="([^"]*)"`)
+)
+
+// absoluteHTML rewrites the root-relative URLs of an HTML fragment so it
+// still works once a feed reader displays it outside the site.
+func absoluteHTML(site config.Config, fragment string) string {
+	fragment = urlAttributePattern.ReplaceAllStringFunc(fragment, func(match string) string {
+		parts := urlAttributePattern.FindStringSubmatch(match)
+
+		return parts[1] + `="` + site.AbsoluteURL(parts[2]) + `"`
+	})
+
+	return srcsetAttributePattern.ReplaceAllStringFunc(fragment, func(match string) string {
+		candidates := strings.Split(srcsetAttributePattern.FindStringSubmatch(match)[1], ",")
+
+		for i, candidate := range candidates {
+			fields := strings.Fields(candidate)
+
+			if len(fields) > 0 && strings.HasPrefix(fields[0], "/") {
+				fields[0] = site.AbsoluteURL(fields[0])
+			}
+
+			candidates[i] = strings.Join(fields, " ")
+		}
+
+		return `srcset="` + strings.Join(candidates, ", ") + `"`
+	})
+}
+
+// enclosure describes the static file at urlPath, e.g. a thumbnail.
+func enclosure(site config.Config, urlPath string) *Enclosure {
+	if urlPath == "" {
+		return nil
+	}
+
+	image := &Enclosure{
+		URL:  site.AbsoluteURL(urlPath),
+		Type: mime.TypeByExtension(path.Ext(urlPath)),
+	}
+
+	if StaticAssets != nil {
+		if info, err := fs.Stat(StaticAssets, strings.TrimPrefix(urlPath, "/static/")); err == nil {
+			image.Length = info.Size()
+		}
+	}
+
+	return image
+}
+
+// New builds a feed of articles written in language, which are expected
+// newest first.
+func New(language string, title string, description string, link string, articles []models.Article) Feed {
+	site := config.Get()
+
+	feed := Feed{
+		Title:       title,
+		Description: description,
+		Link:        site.AbsoluteURL(link),
+		Author:      site.Author,
+		Language:    language,
+	}
+
+	for _, article := range articles {
+		url := site.AbsoluteURL(article.URL())
+
+		item := Item{
+			ID:        url,
+			Title:     article.Data.Title,
+			Link:      url,
+			Summary:   article.Excerpt,
+			Content:   absoluteHTML(site, string(article.Content)),
+			Image:     enclosure(site, article.Data.Thumbnail),
+			Tags:      article.Data.Tags,
+			Published: article.Data.CreatedAt,
+			Updated:   article.Data.LastModified(),
+		}
+
+		if item.Updated.After(feed.Updated) {
+			feed.Updated = item.Updated
+		}
+
+		feed.Items = append(feed.Items, item)
+	}
+
+	return feed
+}
@@ -0,0 +1,104 @@
+package feed
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"coding-kittens.com/models"
+	"coding-kittens.com/modules/config"
+)
+
+func TestAbsoluteHTML(t *testing.T) {
+	site := config.Config{SiteURL: "https://coding-kittens.com"}
+
+	tests := []struct {
+		name     string
+		fragment string
+		want     string
+	}{
+		{
+			name:     "root-relative links",
+			fragment: `<a href="/blog/css">x</a> <img src="/static/a.png"> <video poster="/static/p.png">`,
+			want:     `<a href="https://coding-kittens.com/blog/css">x</a> <img src="https://coding-kittens.com/static/a.png"> <video poster="https://coding-kittens.com/static/p.png">`,
+		},
+		{
+			name:     "srcset candidates",
+			fragment: `<img srcset="/image?w=1 1x, /image?w=2 2x">`,
+			want:     `<img srcset="https://coding-kittens.com/image?w=1 1x, https://coding-kittens.com/image?w=2 2x">`,
+		},
+		{
+			name:     "links left alone",
+			fragment: `<a href="https://example.com/a">x</a> <a href="//example.com/b">y</a> <a href="#top">z</a>`,
+			want:     `<a href="https://example.com/a">x</a> <a href="//example.com/b">y</a> <a href="#top">z</a>`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := absoluteHTML(site, test.fragment); got != test.want {
+				t.Errorf("absoluteHTML(%q) = %q, want %q", test.fragment, got, test.want)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	StaticAssets = fstest.MapFS{"assets/thumbnails/navbar.jpeg": {Data: []byte("jpeg")}}
+	defer func() { StaticAssets = nil }()
+
+	site := config.Get()
+
+	created := time.Date(2024, 1, 20, 10, 0, 0, 0, time.UTC)
+	updated := time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	articles := []models.Article{
+		{
+			Slug:     "navbar",
+			Language: "en",
+			Category: "css",
+			Content:  `<img src="/static/assets/thumbnails/navbar.jpeg">`,
+			Excerpt:  "A navbar",
+			Data: models.FrontMatter{
+				Title:     "Navbar",
+				Thumbnail: "/static/assets/thumbnails/navbar.jpeg",
+				Tags:      []string{"css"},
+				CreatedAt: created,
+				UpdatedAt: updated,
+			},
+		},
+		{
+			Slug:     "recursion",
+			Language: "en",
+			Category: "javascript",
+			Data:     models.FrontMatter{Title: "Recursion", CreatedAt: created},
+		},
+	}
+
+	feed := New("en", "Coding Kittens", "Posts", "/blog", articles)
+
+	if feed.Link != site.AbsoluteURL("/blog") || feed.Language != "en" || !feed.Updated.Equal(updated) {
+		t.Errorf("New() = link %q, language %q, updated %v", feed.Link, feed.Language, feed.Updated)
+	}
+
+	want := Item{
+		ID:        site.AbsoluteURL("/blog/css/navbar"),
+		Title:     "Navbar",
+		Link:      site.AbsoluteURL("/blog/css/navbar"),
+		Summary:   "A navbar",
+		Content:   `<img src="` + site.AbsoluteURL("/static/assets/thumbnails/navbar.jpeg") + `">`,
+		Image:     &Enclosure{URL: site.AbsoluteURL("/static/assets/thumbnails/navbar.jpeg"), Type: "image/jpeg", Length: 4},
+		Tags:      []string{"css"},
+		Published: created,
+		Updated:   updated,
+	}
+
+	if len(feed.Items) != 2 || !reflect.DeepEqual(feed.Items[0], want) {
+		t.Errorf("New() items = %+v, want %+v first", feed.Items, want)
+	}
+
+	if feed.Items[1].Image != nil {
+		t.Errorf("New() image = %+v, want none without a thumbnail", feed.Items[1].Image)
+	}
+}
@@ -0,0 +1,302 @@
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strconv"
+	"time"
+)
+
+// Format serializes a feed, selfURL being the absolute URL it is served at.
+type Format struct {
+	ContentType string
+	Encode      func(feed Feed, selfURL string) ([]byte, error)
+}
+
+const (
+	RSS_MIME_TYPE  = "application/rss+xml"
+	ATOM_MIME_TYPE = "application/atom+xml"
+	JSON_MIME_TYPE = "application/feed+json"
+)
+
+var (
+	RSS  = Format{ContentType: RSS_MIME_TYPE + "; charset=utf-8", Encode: encodeRSS}
+	Atom = Format{ContentType: ATOM_MIME_TYPE + "; charset=utf-8", Encode: encodeAtom}
+	JSON = Format{ContentType: JSON_MIME_TYPE + "; charset=utf-8", Encode: encodeJSON}
+)
+
+type xmlLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr,omitempty"`
+	Type   string `xml:"type,attr,omitempty"`
+	Length string `xml:"length,attr,omitempty"`
+}
+
+type rssFeed struct {
+	XMLName   xml.Name   `xml:"rss"`
+	Version   string     `xml:"version,attr"`
+	AtomNS    string     `xml:"xmlns:atom,attr"`
+	ContentNS string     `xml:"xmlns:content,attr"`
+	MediaNS   string     `xml:"xmlns:media,attr"`
+	Channel   rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language,omitempty"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Self          xmlLink   `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int64  `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+type rssThumbnail struct {
+	URL string `xml:"url,attr"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	GUID        rssGUID       `xml:"guid"`
+	Description string        `xml:"description,omitempty"`
+	Content     string        `xml:"content:encoded"`
+	PubDate     string        `xml:"pubDate,omitempty"`
+	Updated     string        `xml:"atom:updated,omitempty"`
+	Categories  []string      `xml:"category"`
+	Enclosure   *rssEnclosure `xml:"enclosure"`
+	Thumbnail   *rssThumbnail `xml:"media:thumbnail"`
+}
+
+func rssDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(time.RFC1123Z)
+}
+
+func atomDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+func encodeXML(v interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), body...), nil
+}
+
+func encodeRSS(feed Feed, selfURL string) ([]byte, error) {
+	channel := rssChannel{
+		Title:         feed.Title,
+		Link:          feed.Link,
+		Description:   feed.Description,
+		Language:      feed.Language,
+		LastBuildDate: rssDate(feed.Updated),
+		Self:          xmlLink{Href: selfURL, Rel: "self", Type: RSS_MIME_TYPE},
+	}
+
+	for _, item := range feed.Items {
+		rss := rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			GUID:        rssGUID{Value: item.ID, IsPermaLink: item.ID == item.Link},
+			Description: item.Summary,
+			Content:     item.Content,
+			PubDate:     rssDate(item.Published),
+			Updated:     atomDate(item.Updated),
+			Categories:  item.Tags,
+		}
+
+		if item.Image != nil {
+			rss.Enclosure = &rssEnclosure{URL: item.Image.URL, Length: item.Image.Length, Type: item.Image.Type}
+			rss.Thumbnail = &rssThumbnail{URL: item.Image.URL}
+		}
+
+		channel.Items = append(channel.Items, rss)
+	}
+
+	return encodeXML(rssFeed{
+		Version:   "2.0",
+		AtomNS:    "http://www.w3.org/2005/Atom",
+		ContentNS: "http://purl.org/rss/1.0/modules/content/",
+		MediaNS:   "http://search.yahoo.com/mrss/",
+		Channel:   channel,
+	})
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Lang     string      `xml:"xml:lang,attr,omitempty"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	ID       string      `xml:"id"`
+	Updated  string      `xml:"updated"`
+	Links    []xmlLink   `xml:"link"`
+	Author   *atomPerson `xml:"author"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Links      []xmlLink      `xml:"link"`
+	Published  string         `xml:"published,omitempty"`
+	Updated    string         `xml:"updated"`
+	Summary    *atomText      `xml:"summary"`
+	Content    atomText       `xml:"content"`
+	Categories []atomCategory `xml:"category"`
+}
+
+func encodeAtom(feed Feed, selfURL string) ([]byte, error) {
+	atom := atomFeed{
+		Lang:     feed.Language,
+		Title:    feed.Title,
+		Subtitle: feed.Description,
+		ID:       feed.Link,
+		Updated:  atomDate(feed.Updated),
+		Links: []xmlLink{
+			{Href: selfURL, Rel: "self", Type: ATOM_MIME_TYPE},
+			{Href: feed.Link, Rel: "alternate", Type: "text/html"},
+		},
+	}
+
+	if feed.Author != "" {
+		atom.Author = &atomPerson{Name: feed.Author}
+	}
+
+	if atom.Updated == "" {
+		atom.Updated = atomDate(time.Now())
+	}
+
+	for _, item := range feed.Items {
+		entry := atomEntry{
+			Title:     item.Title,
+			ID:        item.ID,
+			Links:     []xmlLink{{Href: item.Link, Rel: "alternate", Type: "text/html"}},
+			Published: atomDate(item.Published),
+			Updated:   atomDate(item.Updated),
+			Content:   atomText{Type: "html", Value: item.Content},
+		}
+
+		if entry.Updated == "" {
+			entry.Updated = atom.Updated
+		}
+
+		if item.Summary != "" {
+			entry.Summary = &atomText{Type: "text", Value: item.Summary}
+		}
+
+		if item.Image != nil {
+			entry.Links = append(entry.Links, xmlLink{
+				Href:   item.Image.URL,
+				Rel:    "enclosure",
+				Type:   item.Image.Type,
+				Length: strconv.FormatInt(item.Image.Length, 10),
+			})
+		}
+
+		for _, tag := range item.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+
+		atom.Entries = append(atom.Entries, entry)
+	}
+
+	return encodeXML(atom)
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeed struct {
+	Version     string       `json:"version"`
+	Title       string       `json:"title"`
+	HomePageURL string       `json:"home_page_url"`
+	FeedURL     string       `json:"feed_url"`
+	Description string       `json:"description,omitempty"`
+	Language    string       `json:"language,omitempty"`
+	Authors     []jsonAuthor `json:"authors,omitempty"`
+	Items       []jsonItem   `json:"items"`
+}
+
+type jsonItem struct {
+	ID            string   `json:"id"`
+	URL           string   `json:"url"`
+	Title         string   `json:"title"`
+	ContentHTML   string   `json:"content_html"`
+	Summary       string   `json:"summary,omitempty"`
+	Image         string   `json:"image,omitempty"`
+	DatePublished string   `json:"date_published,omitempty"`
+	DateModified  string   `json:"date_modified,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+func encodeJSON(feed Feed, selfURL string) ([]byte, error) {
+	document := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       feed.Title,
+		HomePageURL: feed.Link,
+		FeedURL:     selfURL,
+		Description: feed.Description,
+		Language:    feed.Language,
+		Items:       []jsonItem{},
+	}
+
+	if feed.Author != "" {
+		document.Authors = []jsonAuthor{{Name: feed.Author}}
+	}
+
+	for _, item := range feed.Items {
+		entry := jsonItem{
+			ID:            item.ID,
+			URL:           item.Link,
+			Title:         item.Title,
+			ContentHTML:   item.Content,
+			Summary:       item.Summary,
+			DatePublished: atomDate(item.Published),
+			DateModified:  atomDate(item.Updated),
+			Tags:          item.Tags,
+		}
+
+		if item.Image != nil {
+			entry.Image = item.Image.URL
+		}
+
+		document.Items = append(document.Items, entry)
+	}
+
+	return json.MarshalIndent(document, "", "  ")
+}
@@ -0,0 +1,135 @@
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testFeed = Feed{
+	Title:       "Coding Kittens",
+	Description: "Posts",
+	Link:        "https://coding-kittens.com/blog",
+	Author:      "Javier",
+	Language:    "en",
+	Updated:     time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC),
+	Items: []Item{
+		{
+			ID:        "https://coding-kittens.com/blog/css/navbar",
+			Title:     "Navbar & more",
+			Link:      "https://coding-kittens.com/blog/css/navbar",
+			Summary:   "A navbar",
+			Content:   "<p>Body</p>",
+			Image:     &Enclosure{URL: "https://coding-kittens.com/static/navbar.jpeg", Type: "image/jpeg", Length: 4},
+			Tags:      []string{"css", "html"},
+			Published: time.Date(2024, 1, 20, 10, 0, 0, 0, time.UTC),
+			Updated:   time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC),
+		},
+	},
+}
+
+func TestEncodeRSS(t *testing.T) {
+	body, err := encodeRSS(testFeed, "https://coding-kittens.com/feed.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded rssFeed
+	if err := xml.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("encodeRSS() isn't valid XML: %v", err)
+	}
+
+	channel := decoded.Channel
+	if channel.Title != testFeed.Title || channel.Language != "en" || channel.LastBuildDate != "Sat, 02 Mar 2024 10:00:00 +0000" {
+		t.Errorf("encodeRSS() channel = %+v", channel)
+	}
+
+	if len(channel.Items) != 1 {
+		t.Fatalf("encodeRSS() items = %d, want 1", len(channel.Items))
+	}
+
+	item := channel.Items[0]
+	if item.Title != "Navbar & more" || !item.GUID.IsPermaLink || item.PubDate != "Sat, 20 Jan 2024 10:00:00 +0000" {
+		t.Errorf("encodeRSS() item = %+v", item)
+	}
+
+	if item.Enclosure == nil || item.Enclosure.Length != 4 || len(item.Categories) != 2 {
+		t.Errorf("encodeRSS() enclosure = %+v, categories = %q", item.Enclosure, item.Categories)
+	}
+
+	// The full body goes in its namespaced element, escaped
+	if !strings.Contains(string(body), "<content:encoded>&lt;p&gt;Body&lt;/p&gt;</content:encoded>") {
+		t.Errorf("encodeRSS() = %s, want the content encoded", body)
+	}
+}
+
+func TestEncodeAtom(t *testing.T) {
+	body, err := encodeAtom(testFeed, "https://coding-kittens.com/atom.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded atomFeed
+	if err := xml.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("encodeAtom() isn't valid XML: %v", err)
+	}
+
+	if decoded.ID != testFeed.Link || decoded.Updated != "2024-03-02T10:00:00Z" || decoded.Author == nil || len(decoded.Links) != 2 {
+		t.Errorf("encodeAtom() feed = %+v", decoded)
+	}
+
+	if len(decoded.Entries) != 1 {
+		t.Fatalf("encodeAtom() entries = %d, want 1", len(decoded.Entries))
+	}
+
+	entry := decoded.Entries[0]
+	if entry.Published != "2024-01-20T10:00:00Z" || entry.Summary == nil || entry.Content.Type != "html" || len(entry.Links) != 2 || len(entry.Categories) != 2 {
+		t.Errorf("encodeAtom() entry = %+v", entry)
+	}
+}
+
+func TestEncodeAtomWithoutDates(t *testing.T) {
+	feed := Feed{Title: "Empty", Link: "https://coding-kittens.com/blog", Items: []Item{{ID: "a", Title: "A"}}}
+
+	body, err := encodeAtom(feed, "https://coding-kittens.com/atom.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded atomFeed
+	if err := xml.Unmarshal(body, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	// Atom requires an updated date on the feed and on every entry
+	if decoded.Updated == "" || decoded.Entries[0].Updated != decoded.Updated {
+		t.Errorf("encodeAtom() updated = %q, entry %q", decoded.Updated, decoded.Entries[0].Updated)
+	}
+}
+
+func TestEncodeJSON(t *testing.T) {
+	body, err := encodeJSON(testFeed, "https://coding-kittens.com/feed.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded jsonFeed
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("encodeJSON() isn't valid JSON: %v", err)
+	}
+
+	if !strings.HasPrefix(decoded.Version, "https://jsonfeed.org/version/") || decoded.FeedURL != "https://coding-kittens.com/feed.json" || len(decoded.Authors) != 1 {
+		t.Errorf("encodeJSON() feed = %+v", decoded)
+	}
+
+	if len(decoded.Items) != 1 || decoded.Items[0].Image != testFeed.Items[0].Image.URL || decoded.Items[0].DateModified != "2024-03-02T10:00:00Z" {
+		t.Errorf("encodeJSON() items = %+v", decoded.Items)
+	}
+
+	// Empty feeds still list their items as an array
+	if body, _ := encodeJSON(Feed{}, ""); !strings.Contains(string(body), `"items": []`) {
+		t.Errorf("encodeJSON() of an empty feed = %s", body)
+	}
+}
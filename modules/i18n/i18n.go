@@ -0,0 +1,118 @@
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DEFAULT_LANGUAGE is served without a path prefix, every other language
+// under its own, e.g. /es/blog.
+const DEFAULT_LANGUAGE = "en"
+
+// LANGUAGES are the languages the site is written in, the default first.
+var LANGUAGES = []string{DEFAULT_LANGUAGE, "es"}
+
+// COOKIE_NAME remembers the language picked through the language switch,
+// which links to pages with the QUERY_PARAMETER set.
+const (
+	COOKIE_NAME     = "lang"
+	QUERY_PARAMETER = "lang"
+)
+
+// Supported reports whether the site is written in language.
+func Supported(language string) bool {
+	for _, supported := range LANGUAGES {
+		if language == supported {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Localize prefixes path with language, e.g. "/blog" turns into "/es/blog"
+// and "/" into "/es". Paths of the default language are left untouched.
+func Localize(language string, path string) string {
+	if language == "" || language == DEFAULT_LANGUAGE || !Supported(language) {
+		return path
+	}
+
+	if path == "/" {
+		return "/" + language
+	}
+
+	return "/" + language + path
+}
+
+// Split returns the language of path along with the path without its prefix.
+func Split(path string) (string, string) {
+	segment, rest, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+
+	if segment != DEFAULT_LANGUAGE && Supported(segment) {
+		return segment, "/" + rest
+	}
+
+	return DEFAULT_LANGUAGE, path
+}
+
+// Alternate is a version of a page in another language.
+type Alternate struct {
+	Language string
+	URL      string
+}
+
+// Alternates returns the versions of the page at path in every language.
+func Alternates(path string) []Alternate {
+	alternates := make([]Alternate, 0, len(LANGUAGES))
+
+	for _, language := range LANGUAGES {
+		alternates = append(alternates, Alternate{Language: language, URL: Localize(language, path)})
+	}
+
+	return alternates
+}
+
+// Negotiate picks the language of a visitor: the one remembered in the
+// cookie, or else the preferred supported language of the Accept-Language
+// header, or else the default one.
+func Negotiate(cookie string, acceptLanguage string) string {
+	if Supported(cookie) {
+		return cookie
+	}
+
+	type preference struct {
+		language string
+		quality  float64
+	}
+
+	var preferences []preference
+
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, parameters, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+
+		if value, ok := strings.CutPrefix(strings.TrimSpace(parameters), "q="); ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				quality = parsed
+			}
+		}
+
+		// "es-ES" and "es" both ask for Spanish
+		language, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+
+		if Supported(language) && quality > 0 {
+			preferences = append(preferences, preference{language, quality})
+		}
+	}
+
+	sort.SliceStable(preferences, func(i, j int) bool {
+		return preferences[i].quality > preferences[j].quality
+	})
+
+	if len(preferences) > 0 {
+		return preferences[0].language
+	}
+
+	return DEFAULT_LANGUAGE
+}
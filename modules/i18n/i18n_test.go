@@ -0,0 +1,79 @@
+package i18n
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name           string
+		cookie         string
+		acceptLanguage string
+		want           string
+	}{
+		{name: "nothing", want: "en"},
+		{name: "cookie", cookie: "es", acceptLanguage: "en", want: "es"},
+		{name: "unsupported cookie", cookie: "fr", acceptLanguage: "es", want: "es"},
+		{name: "header", acceptLanguage: "es", want: "es"},
+		{name: "region", acceptLanguage: "es-ES,es;q=0.9", want: "es"},
+		{name: "upper case", acceptLanguage: "ES-mx", want: "es"},
+		{name: "quality", acceptLanguage: "en;q=0.5, es;q=0.8", want: "es"},
+		{name: "order breaks ties", acceptLanguage: "en, es", want: "en"},
+		{name: "unsupported skipped", acceptLanguage: "fr-FR, fr;q=0.9, es;q=0.7, en;q=0.5", want: "es"},
+		{name: "refused", acceptLanguage: "es;q=0, en;q=0.1", want: "en"},
+		{name: "only unsupported", acceptLanguage: "fr, de", want: "en"},
+		{name: "wildcard", acceptLanguage: "*", want: "en"},
+		{name: "malformed quality", acceptLanguage: "es;q=abc", want: "es"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Negotiate(test.cookie, test.acceptLanguage); got != test.want {
+				t.Errorf("Negotiate(%q, %q) = %q, want %q", test.cookie, test.acceptLanguage, got, test.want)
+			}
+		})
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	tests := []struct {
+		language string
+		path     string
+		want     string
+	}{
+		{language: "en", path: "/blog", want: "/blog"},
+		{language: "", path: "/blog", want: "/blog"},
+		{language: "fr", path: "/blog", want: "/blog"},
+		{language: "es", path: "/blog", want: "/es/blog"},
+		{language: "es", path: "/", want: "/es"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.language+test.path, func(t *testing.T) {
+			if got := Localize(test.language, test.path); got != test.want {
+				t.Errorf("Localize(%q, %q) = %q, want %q", test.language, test.path, got, test.want)
+			}
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		path     string
+		language string
+		rest     string
+	}{
+		{path: "/", language: "en", rest: "/"},
+		{path: "/blog/css", language: "en", rest: "/blog/css"},
+		{path: "/es", language: "es", rest: "/"},
+		{path: "/es/blog/css", language: "es", rest: "/blog/css"},
+		{path: "/en/blog", language: "en", rest: "/en/blog"},
+		{path: "/espresso", language: "en", rest: "/espresso"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			if language, rest := Split(test.path); language != test.language || rest != test.rest {
+				t.Errorf("Split(%q) = %q, %q, want %q, %q", test.path, language, rest, test.language, test.rest)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Catalogues are the UI strings of every language by message key.
+type Catalogues map[string]map[string]string
+
+var catalogues atomic.Pointer[Catalogues]
+
+// Parse reads the message catalogue of every language, <language>.json, from
+// fsys.
+func Parse(fsys fs.FS) (Catalogues, error) {
+	loaded := Catalogues{}
+
+	for _, language := range LANGUAGES {
+		file, err := fs.ReadFile(fsys, language+".json")
+		if err != nil {
+			return nil, err
+		}
+
+		messages := map[string]string{}
+		if err := json.Unmarshal(file, &messages); err != nil {
+			return nil, fmt.Errorf("%s.json: %w", language, err)
+		}
+
+		loaded[language] = messages
+	}
+
+	return loaded, nil
+}
+
+// Store makes loaded the catalogues the messages are translated with.
+func Store(loaded Catalogues) {
+	catalogues.Store(&loaded)
+}
+
+// Lookup returns the message key in language, or in the default language
+// when it isn't translated.
+func Lookup(language string, key string) (string, bool) {
+	loaded := catalogues.Load()
+	if loaded == nil {
+		return "", false
+	}
+
+	if message, ok := (*loaded)[language][key]; ok {
+		return message, true
+	}
+
+	message, ok := (*loaded)[DEFAULT_LANGUAGE][key]
+
+	return message, ok
+}
+
+// T translates the message key into language, formatting it with args.
+// Missing translations fall back to the default language, then to the key.
+func T(language string, key string, args ...interface{}) string {
+	message, ok := Lookup(language, key)
+	if !ok {
+		message = key
+	}
+
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+
+	return message
+}
+
+// Date formats date with the layout of the "date.<format>" message, e.g.
+// "date.long", and the month names of language.
+func Date(language string, format string, date time.Time) string {
+	formatted := date.Format(T(language, "date."+format))
+
+	month := date.Month().String()
+
+	if name, ok := Lookup(language, "month."+month); ok && strings.Contains(formatted, month) {
+		return strings.Replace(formatted, month, name, 1)
+	}
+
+	if name, ok := Lookup(language, "month."+month[:3]); ok {
+		return strings.Replace(formatted, month[:3], name, 1)
+	}
+
+	return formatted
+}
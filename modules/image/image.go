@@ -1,8 +1,9 @@
 package image
 
 import (
-	"embed"
 	"fmt"
+	"hash/crc32"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -23,7 +24,8 @@ var imageCache = cache.NewFilesystemCache(cacheDir)
 
 var urlPattern = regexp.MustCompile(`^web/static/assets/[^\.]+\.(jpeg|jpg|png|gif|webp|avif)$`)
 
-var StaticAssets embed.FS
+// StaticAssets holds the static files, assigned on startup.
+var StaticAssets fs.FS
 
 func GenerateCacheKey(imageURL string, width, height int, mimeType string) string {
     parts := strings.Split(mimeType, "/")
@@ -39,7 +41,7 @@ func ProcessImage(c *gin.Context) {
         return
     }
     
-    imageBytes, err := StaticAssets.ReadFile(imagePath)
+    imageBytes, err := fs.ReadFile(StaticAssets, strings.TrimPrefix(imagePath, "web/static/"))
     if err != nil {
         c.JSON(400, gin.H{"error": "Failed to read the image"})
         return
@@ -87,7 +89,8 @@ func ProcessImage(c *gin.Context) {
         return
     }
 
-    cacheKey := GenerateCacheKey(imagePath, width, height, mimeType)
+    // The checksum tells apart images replaced in the content directory
+    cacheKey := GenerateCacheKey(fmt.Sprintf("%s_%08x", imagePath, crc32.ChecksumIEEE(imageBytes)), width, height, mimeType)
 
     if cachedImage, mimeType, err := imageCache.Get(cacheKey); err == nil {
         c.Data(200, mimeType, cachedImage)
@@ -0,0 +1,195 @@
+package image
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"html"
+	"io/fs"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/h2non/bimg"
+)
+
+// Size of the Open Graph cards, the one social networks recommend.
+const (
+	CARD_WIDTH  = 1200
+	CARD_HEIGHT = 630
+)
+
+// CARD_VERSION is part of the cache keys of the cards, so changing their
+// layout renders them again.
+const CARD_VERSION = 2
+
+// DEFAULT_ACCENT colours the cards when the stylesheet has no accent.
+const DEFAULT_ACCENT = "#e88009"
+
+// Card is what the Open Graph card of an article shows.
+type Card struct {
+	Thumbnail string // static path of the background, e.g. /static/assets/thumbnails/post.jpeg
+	Title     string
+	Subtitle  string
+	SiteName  string
+	Accent    string // hex colour of the bar and the site name
+}
+
+// OpenGraphCard renders card as a PNG, or returns it from the cache when it
+// was rendered before.
+func OpenGraphCard(card Card) ([]byte, error) {
+	if !strings.HasPrefix(card.Accent, "#") {
+		card.Accent = DEFAULT_ACCENT
+	}
+
+	// Cards without a readable thumbnail get a plain background
+	thumbnail, err := fs.ReadFile(StaticAssets, strings.TrimPrefix(card.Thumbnail, "/static/"))
+	if err != nil {
+		thumbnail = nil
+	}
+
+	cacheKey := cardCacheKey(card, thumbnail)
+
+	if cachedImage, _, err := imageCache.Get(cacheKey); err == nil {
+		return cachedImage, nil
+	}
+
+	image, err := renderCard(card, thumbnail)
+	if err != nil {
+		return nil, err
+	}
+
+	imageCache.Set(cacheKey, image, "image/png", 7*24*time.Hour)
+
+	return image, nil
+}
+
+func cardCacheKey(card Card, thumbnail []byte) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%d\x00%s\x00%s\x00%s\x00%s\x00", CARD_VERSION, card.Title, card.Subtitle, card.SiteName, card.Accent)
+	hash.Write(thumbnail)
+
+	return fmt.Sprintf("og/%x.png", hash.Sum(nil))
+}
+
+// renderCard draws the text of the card over its thumbnail, cropped to the
+// size of the card.
+func renderCard(card Card, thumbnail []byte) ([]byte, error) {
+	overlay, err := bimg.NewImage(cardSVG(card, thumbnail == nil)).Convert(bimg.PNG)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render the text of the card: %w", err)
+	}
+
+	if thumbnail == nil {
+		return overlay, nil
+	}
+
+	background, err := bimg.Resize(thumbnail, bimg.Options{
+		Width:   CARD_WIDTH,
+		Height:  CARD_HEIGHT,
+		Crop:    true,
+		Enlarge: true,
+		Gravity: bimg.GravitySmart,
+		Type:    bimg.PNG,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to crop the thumbnail: %w", err)
+	}
+
+	image, err := bimg.NewImage(background).WatermarkImage(bimg.WatermarkImage{Buf: overlay, Opacity: 1})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compose the card: %w", err)
+	}
+
+	return image, nil
+}
+
+// Layout of the text of the cards, in pixels.
+const (
+	cardMargin         = 80
+	titleSize          = 72
+	titleLineHeight    = 80
+	titleLineLength    = 30 // characters
+	titleMaxLines      = 3
+	subtitleSize       = 40
+	subtitleLineHeight = 52
+	subtitleLineLength = 46
+	subtitleMaxLines   = 2
+)
+
+// cardSVG lays the text of the card out at its bottom, over a gradient that
+// keeps it readable on any thumbnail, or over a plain background.
+func cardSVG(card Card, opaque bool) []byte {
+	var svg strings.Builder
+
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, CARD_WIDTH, CARD_HEIGHT, CARD_WIDTH, CARD_HEIGHT)
+	svg.WriteString(`<defs><linearGradient id="shade" x1="0" y1="0" x2="0" y2="1">`)
+	svg.WriteString(`<stop offset="0" stop-color="#000" stop-opacity="0.2"/><stop offset="1" stop-color="#000" stop-opacity="0.85"/>`)
+	svg.WriteString(`</linearGradient></defs>`)
+
+	if opaque {
+		svg.WriteString(`<rect width="100%" height="100%" fill="#1c1917"/>`)
+	}
+
+	svg.WriteString(`<rect width="100%" height="100%" fill="url(#shade)"/>`)
+	fmt.Fprintf(&svg, `<rect width="24" height="100%%" fill="%s"/>`, html.EscapeString(card.Accent))
+
+	fmt.Fprintf(&svg, `<text x="%d" y="%d" font-family="Oxygen Mono, monospace" font-size="32" fill="%s">%s</text>`,
+		cardMargin, cardMargin+32, html.EscapeString(card.Accent), html.EscapeString(card.SiteName))
+
+	title := wrapText(card.Title, titleLineLength, titleMaxLines)
+	subtitle := wrapText(card.Subtitle, subtitleLineLength, subtitleMaxLines)
+
+	// Lines are stacked up from the bottom margin
+	y := CARD_HEIGHT - cardMargin - (len(subtitle)-1)*subtitleLineHeight
+
+	if len(subtitle) > 0 {
+		writeLines(&svg, subtitle, y, subtitleLineHeight, fmt.Sprintf(`font-family="Montserrat, sans-serif" font-size="%d" fill="#e7e5e4"`, subtitleSize))
+		y -= subtitleLineHeight + 16
+	} else {
+		y = CARD_HEIGHT - cardMargin
+	}
+
+	y -= (len(title) - 1) * titleLineHeight
+
+	writeLines(&svg, title, y, titleLineHeight, fmt.Sprintf(`font-family="Barlow Condensed, sans-serif" font-weight="bold" font-size="%d" fill="#fff"`, titleSize))
+
+	svg.WriteString(`</svg>`)
+
+	return []byte(svg.String())
+}
+
+func writeLines(svg *strings.Builder, lines []string, y int, lineHeight int, attributes string) {
+	for i, line := range lines {
+		fmt.Fprintf(svg, `<text x="%d" y="%d" %s>%s</text>`, cardMargin, y+i*lineHeight, attributes, html.EscapeString(line))
+	}
+}
+
+// wrapText breaks text into lines of at most width characters, cutting it
+// short with an ellipsis past maxLines.
+func wrapText(text string, width int, maxLines int) []string {
+	var lines []string
+	var line string
+
+	for _, word := range strings.Fields(text) {
+		switch {
+		case line == "":
+			line = word
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) > width:
+			lines = append(lines, line)
+			line = word
+		default:
+			line += " " + word
+		}
+	}
+
+	if line != "" {
+		lines = append(lines, line)
+	}
+
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+		lines[maxLines-1] = strings.TrimRight(lines[maxLines-1], ".,;:") + "…"
+	}
+
+	return lines
+}
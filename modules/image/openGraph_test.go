@@ -0,0 +1,33 @@
+package image
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		width    int
+		maxLines int
+		want     []string
+	}{
+		{name: "empty", text: "", width: 10, maxLines: 2, want: nil},
+		{name: "one line", text: "Sticky navbar", width: 20, maxLines: 2, want: []string{"Sticky navbar"}},
+		{name: "wrapped", text: "A sticky navbar that shrinks", width: 15, maxLines: 3, want: []string{"A sticky navbar", "that shrinks"}},
+		{name: "extra spaces", text: "  A   sticky\nnavbar ", width: 8, maxLines: 3, want: []string{"A sticky", "navbar"}},
+		{name: "runes", text: "Diseño ágil más útil", width: 11, maxLines: 2, want: []string{"Diseño ágil", "más útil"}},
+		{name: "long word", text: "Supercalifragilistic CSS", width: 10, maxLines: 3, want: []string{"Supercalifragilistic", "CSS"}},
+		{name: "ellipsis", text: "One two three four five six", width: 7, maxLines: 2, want: []string{"One two", "three…"}},
+		{name: "punctuation trimmed", text: "First, second; third fourth", width: 7, maxLines: 2, want: []string{"First,", "second…"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := wrapText(test.text, test.width, test.maxLines); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("wrapText(%q, %d, %d) = %q, want %q", test.text, test.width, test.maxLines, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package jsonld
+
+import (
+	"time"
+
+	"coding-kittens.com/models"
+	"coding-kittens.com/modules/config"
+)
+
+// CONTEXT is the vocabulary every structured data document uses.
+const CONTEXT = "https://schema.org"
+
+// Person is the author of the site or of an article.
+type Person struct {
+	Context string   `json:"@context,omitempty"`
+	Type    string   `json:"@type"`
+	Name    string   `json:"name"`
+	URL     string   `json:"url,omitempty"`
+	Image   string   `json:"image,omitempty"`
+	SameAs  []string `json:"sameAs,omitempty"`
+}
+
+// NewPerson describes the author of the site.
+func NewPerson(site config.Config) Person {
+	person := Person{
+		Context: CONTEXT,
+		Type:    "Person",
+		Name:    site.Author,
+		URL:     site.AbsoluteURL("/"),
+		SameAs:  site.Profiles,
+	}
+
+	if site.AuthorImage != "" {
+		person.Image = site.AbsoluteURL(site.AuthorImage)
+	}
+
+	return person
+}
+
+// BreadcrumbList is the navigation trail leading to a page.
+type BreadcrumbList struct {
+	Context         string     `json:"@context"`
+	Type            string     `json:"@type"`
+	ItemListElement []ListItem `json:"itemListElement"`
+}
+
+// ListItem is a step of a BreadcrumbList.
+type ListItem struct {
+	Type     string `json:"@type"`
+	Position int    `json:"position"`
+	Name     string `json:"name"`
+	Item     string `json:"item,omitempty"`
+}
+
+// NewBreadcrumbList describes the breadcrumbs of a page, ending with the page
+// itself.
+func NewBreadcrumbList(site config.Config, breadcrumbs []models.Breadcrumb, page models.Breadcrumb) BreadcrumbList {
+	list := BreadcrumbList{Context: CONTEXT, Type: "BreadcrumbList"}
+
+	steps := append(append([]models.Breadcrumb{}, breadcrumbs...), page)
+
+	for i, breadcrumb := range steps {
+		list.ItemListElement = append(list.ItemListElement, ListItem{
+			Type:     "ListItem",
+			Position: i + 1,
+			Name:     breadcrumb.Name,
+			Item:     site.AbsoluteURL(breadcrumb.URL),
+		})
+	}
+
+	return list
+}
+
+// BlogPosting describes an article.
+type BlogPosting struct {
+	Context          string   `json:"@context"`
+	Type             string   `json:"@type"`
+	Headline         string   `json:"headline"`
+	Description      string   `json:"description,omitempty"`
+	Image            []string `json:"image,omitempty"`
+	DatePublished    string   `json:"datePublished,omitempty"`
+	DateModified     string   `json:"dateModified,omitempty"`
+	Author           Person   `json:"author"`
+	MainEntityOfPage string   `json:"mainEntityOfPage"`
+	InLanguage       string   `json:"inLanguage"`
+	ArticleSection   string   `json:"articleSection,omitempty"`
+	Keywords         []string `json:"keywords,omitempty"`
+	WordCount        int      `json:"wordCount"`
+}
+
+// NewBlogPosting describes article, whose canonical URL and images are given
+// as paths or absolute URLs.
+func NewBlogPosting(site config.Config, article models.Article, description string, canonical string, images []string) BlogPosting {
+	author := NewPerson(site)
+	author.Context = ""
+
+	// Guest authors are only known by name
+	if article.Data.Author != "" && article.Data.Author != site.Author {
+		author = Person{Type: "Person", Name: article.Data.Author}
+	}
+
+	posting := BlogPosting{
+		Context:          CONTEXT,
+		Type:             "BlogPosting",
+		Headline:         article.Data.Title,
+		Description:      description,
+		DatePublished:    date(article.Data.CreatedAt),
+		DateModified:     date(article.Data.LastModified()),
+		Author:           author,
+		MainEntityOfPage: site.AbsoluteURL(canonical),
+		InLanguage:       article.Language,
+		ArticleSection:   article.Category,
+		Keywords:         article.Data.Tags,
+		WordCount:        article.WordCount,
+	}
+
+	for _, image := range images {
+		posting.Image = append(posting.Image, site.AbsoluteURL(image))
+	}
+
+	return posting
+}
+
+func date(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
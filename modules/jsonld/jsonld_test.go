@@ -0,0 +1,126 @@
+package jsonld
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"coding-kittens.com/models"
+	"coding-kittens.com/modules/config"
+)
+
+var site = config.Config{
+	SiteURL:     "https://coding-kittens.com",
+	SiteName:    "Coding Kittens",
+	Author:      "Javier Muñoz Tous",
+	AuthorImage: "/static/assets/me.jpeg",
+	Profiles:    []string{"https://github.com/coding-kittens"},
+}
+
+func TestNewPerson(t *testing.T) {
+	got, err := json.Marshal(NewPerson(site))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"@context":"https://schema.org","@type":"Person","name":"Javier Muñoz Tous","url":"https://coding-kittens.com/","image":"https://coding-kittens.com/static/assets/me.jpeg","sameAs":["https://github.com/coding-kittens"]}`
+	if string(got) != want {
+		t.Errorf("NewPerson() = %s, want %s", got, want)
+	}
+
+	withoutImage := site
+	withoutImage.AuthorImage = ""
+
+	if person := NewPerson(withoutImage); person.Image != "" {
+		t.Errorf("NewPerson() image = %q, want none", person.Image)
+	}
+}
+
+func TestNewBreadcrumbList(t *testing.T) {
+	breadcrumbs := []models.Breadcrumb{{Name: "Blog", URL: "/blog"}, {Name: "CSS", URL: "/blog/css"}}
+	list := NewBreadcrumbList(site, breadcrumbs, models.Breadcrumb{Name: "Navbar", URL: "/blog/css/navbar"})
+
+	want := []ListItem{
+		{Type: "ListItem", Position: 1, Name: "Blog", Item: "https://coding-kittens.com/blog"},
+		{Type: "ListItem", Position: 2, Name: "CSS", Item: "https://coding-kittens.com/blog/css"},
+		{Type: "ListItem", Position: 3, Name: "Navbar", Item: "https://coding-kittens.com/blog/css/navbar"},
+	}
+
+	if !reflect.DeepEqual(list.ItemListElement, want) {
+		t.Errorf("NewBreadcrumbList() = %+v, want %+v", list.ItemListElement, want)
+	}
+
+	if len(breadcrumbs) != 2 {
+		t.Errorf("NewBreadcrumbList() changed the breadcrumbs to %+v", breadcrumbs)
+	}
+}
+
+func TestNewBlogPosting(t *testing.T) {
+	created := time.Date(2024, 1, 2, 10, 0, 0, 0, time.FixedZone("CET", 3600))
+	updated := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	article := models.Article{
+		Category:  "css",
+		Language:  "en",
+		WordCount: 420,
+		Data: models.FrontMatter{
+			Title:     "Sticky navbar",
+			Tags:      []string{"css", "animations"},
+			CreatedAt: created,
+			UpdatedAt: updated,
+		},
+	}
+
+	images := []string{"/og/blog/css/navbar.png", "https://images.example.com/navbar.jpeg"}
+
+	got, err := json.Marshal(NewBlogPosting(site, article, "A navbar", "/blog/css/navbar", images))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"@context":"https://schema.org","@type":"BlogPosting","headline":"Sticky navbar","description":"A navbar",` +
+		`"image":["https://coding-kittens.com/og/blog/css/navbar.png","https://images.example.com/navbar.jpeg"],` +
+		`"datePublished":"2024-01-02T10:00:00+01:00","dateModified":"2024-03-04T10:00:00Z",` +
+		`"author":{"@type":"Person","name":"Javier Muñoz Tous","url":"https://coding-kittens.com/","image":"https://coding-kittens.com/static/assets/me.jpeg","sameAs":["https://github.com/coding-kittens"]},` +
+		`"mainEntityOfPage":"https://coding-kittens.com/blog/css/navbar","inLanguage":"en","articleSection":"css","keywords":["css","animations"],"wordCount":420}`
+	if string(got) != want {
+		t.Errorf("NewBlogPosting() = %s\nwant %s", got, want)
+	}
+
+	tests := []struct {
+		name   string
+		author string
+		want   Person
+	}{
+		{name: "site author", author: "Javier Muñoz Tous", want: Person{Type: "Person", Name: site.Author, URL: "https://coding-kittens.com/", Image: "https://coding-kittens.com/static/assets/me.jpeg", SameAs: site.Profiles}},
+		{name: "guest author", author: "Ada Lovelace", want: Person{Type: "Person", Name: "Ada Lovelace"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			article.Data.Author = test.author
+
+			if posting := NewBlogPosting(site, article, "", "/blog/css/navbar", nil); !reflect.DeepEqual(posting.Author, test.want) {
+				t.Errorf("NewBlogPosting() author = %+v, want %+v", posting.Author, test.want)
+			}
+		})
+	}
+
+	// Articles without dates leave them out
+	undated, err := json.Marshal(NewBlogPosting(site, models.Article{}, "", "/", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(undated, &fields); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, field := range []string{"datePublished", "dateModified", "image"} {
+		if _, ok := fields[field]; ok {
+			t.Errorf("NewBlogPosting() = %s, want no %s", undated, field)
+		}
+	}
+}
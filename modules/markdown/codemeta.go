@@ -0,0 +1,187 @@
+package markdown
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// codeMeta holds what the info string of a fence asks for, e.g.
+// ```js title="index.js" {3-5,8} showLineNumbers or ```diff-css.
+type codeMeta struct {
+	Language    string
+	Title       string
+	Highlighted []lineRange
+	LineNumbers bool
+	StartLine   int
+	Diff        bool
+}
+
+// lineRange is a range of 1-based line numbers, last included.
+type lineRange struct {
+	First int
+	Last  int
+}
+
+// highlights reports whether the ranges of the info string highlight line.
+// They are kept as written, ranges past the end of the code being as cheap
+// as the others.
+func (m codeMeta) highlights(line int) bool {
+	for _, lineRange := range m.Highlighted {
+		if line >= lineRange.First && line <= lineRange.Last {
+			return true
+		}
+	}
+
+	return false
+}
+
+var (
+	titlePattern       = regexp.MustCompile(`\btitle=(?:"([^"]*)"|'([^']*)')`)
+	lineNumbersPattern = regexp.MustCompile(`\bshowLineNumbers(?:\{(\d+)\})?`)
+	lineRangesPattern  = regexp.MustCompile(`\{([\d\s,-]+)\}`)
+)
+
+// parseCodeMeta reads the info string of a fenced code block.
+func parseCodeMeta(info string) codeMeta {
+	meta := codeMeta{StartLine: 1}
+
+	info = strings.TrimSpace(info)
+
+	if fields := strings.Fields(info); len(fields) > 0 && !strings.ContainsAny(fields[0], "={") {
+		meta.Language = fields[0]
+		info = strings.TrimPrefix(info, fields[0])
+	}
+
+	if language, ok := strings.CutPrefix(meta.Language, "diff-"); ok {
+		meta.Diff = true
+		meta.Language = language
+	}
+
+	if match := titlePattern.FindStringSubmatch(info); match != nil {
+		meta.Title = match[1] + match[2]
+		info = strings.Replace(info, match[0], "", 1)
+	}
+
+	if match := lineNumbersPattern.FindStringSubmatch(info); match != nil {
+		meta.LineNumbers = true
+
+		if start, err := strconv.Atoi(match[1]); err == nil && start > 0 {
+			meta.StartLine = start
+		}
+
+		info = strings.Replace(info, match[0], "", 1)
+	}
+
+	for _, match := range lineRangesPattern.FindAllStringSubmatch(info, -1) {
+		for _, part := range strings.Split(match[1], ",") {
+			from, to, isRange := strings.Cut(strings.TrimSpace(part), "-")
+
+			first, err := strconv.Atoi(strings.TrimSpace(from))
+			if err != nil {
+				continue
+			}
+
+			last := first
+			if isRange {
+				if last, err = strconv.Atoi(strings.TrimSpace(to)); err != nil {
+					continue
+				}
+			}
+
+			if first <= last {
+				meta.Highlighted = append(meta.Highlighted, lineRange{First: first, Last: last})
+			}
+		}
+	}
+
+	return meta
+}
+
+// codeLine is a source line of a code block along with its decorations.
+type codeLine struct {
+	Text        string
+	Highlighted bool
+	Focused     bool
+	Added       bool
+	Removed     bool
+}
+
+// notationPattern matches a trailing shiki style notation comment such as
+// `// [!code ++]`, `/* [!code focus] */` or `<!-- [!code highlight] -->`.
+var notationPattern = regexp.MustCompile(`\s*(?://|/\*|<!--|#)\s*\[!code\s+(\+\+|--|focus|highlight|hl)\]\s*(?:\*/|-->)?\s*$`)
+
+// codeLines splits the code of a block into lines, taking the notation
+// comments and the diff markers out of the code and into the decorations.
+func codeLines(code string, meta codeMeta) []codeLine {
+	code = strings.TrimSuffix(code, "\n")
+
+	var lines []codeLine
+
+	for i, text := range strings.Split(code, "\n") {
+		line := codeLine{Highlighted: meta.highlights(i + 1)}
+
+		for {
+			match := notationPattern.FindStringSubmatchIndex(text)
+			if match == nil {
+				break
+			}
+
+			switch text[match[2]:match[3]] {
+			case "++":
+				line.Added = true
+			case "--":
+				line.Removed = true
+			case "focus":
+				line.Focused = true
+			default:
+				line.Highlighted = true
+			}
+
+			text = text[:match[0]]
+		}
+
+		if meta.Diff {
+			switch {
+			case strings.HasPrefix(text, "+"):
+				line.Added = true
+				text = text[1:]
+			case strings.HasPrefix(text, "-"):
+				line.Removed = true
+				text = text[1:]
+			}
+		}
+
+		line.Text = text
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// attributes returns the HTML attributes of the span wrapping the line.
+func (l codeLine) attributes() string {
+	var classes []string
+
+	if l.Added {
+		classes = append(classes, "diff", "add")
+	} else if l.Removed {
+		classes = append(classes, "diff", "remove")
+	}
+
+	if l.Focused {
+		classes = append(classes, "focused")
+	}
+
+	attributes := `data-line=""`
+
+	if l.Highlighted {
+		attributes += ` data-highlighted-line=""`
+	}
+
+	if len(classes) > 0 {
+		attributes += ` class="` + strings.Join(classes, " ") + `"`
+	}
+
+	return attributes
+}
@@ -0,0 +1,124 @@
+package markdown
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCodeMeta(t *testing.T) {
+	tests := []struct {
+		info string
+		want codeMeta
+	}{
+		{
+			info: "",
+			want: codeMeta{StartLine: 1},
+		},
+		{
+			info: "js",
+			want: codeMeta{Language: "js", StartLine: 1},
+		},
+		{
+			info: `js title="index.js" {3-5,8} showLineNumbers`,
+			want: codeMeta{
+				Language:    "js",
+				Title:       "index.js",
+				Highlighted: []lineRange{{First: 3, Last: 5}, {First: 8, Last: 8}},
+				LineNumbers: true,
+				StartLine:   1,
+			},
+		},
+		{
+			info: `css title='styles.css' showLineNumbers{10}`,
+			want: codeMeta{
+				Language:    "css",
+				Title:       "styles.css",
+				LineNumbers: true,
+				StartLine:   10,
+			},
+		},
+		{
+			info: "diff-css {1}",
+			want: codeMeta{Language: "css", Highlighted: []lineRange{{First: 1, Last: 1}}, StartLine: 1, Diff: true},
+		},
+		{
+			// Metadata without a language
+			info: `title="notes" { 2 , 4-3 }`,
+			want: codeMeta{Title: "notes", Highlighted: []lineRange{{First: 2, Last: 2}}, StartLine: 1},
+		},
+		{
+			// Ranges aren't expanded, however far they go
+			info: "go {2-1000000000}",
+			want: codeMeta{Language: "go", Highlighted: []lineRange{{First: 2, Last: 1000000000}}, StartLine: 1},
+		},
+		{
+			info: "ts showLineNumbers{0} {a-b}",
+			want: codeMeta{Language: "ts", LineNumbers: true, StartLine: 1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.info, func(t *testing.T) {
+			if got := parseCodeMeta(test.info); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("parseCodeMeta(%q) = %+v, want %+v", test.info, got, test.want)
+			}
+		})
+	}
+}
+
+func TestCodeLines(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		meta codeMeta
+		want []codeLine
+	}{
+		{
+			name: "highlighted lines",
+			code: "a\nb\n",
+			meta: codeMeta{Highlighted: []lineRange{{First: 2, Last: 2}}},
+			want: []codeLine{{Text: "a"}, {Text: "b", Highlighted: true}},
+		},
+		{
+			name: "range past the end",
+			code: "a\nb\n",
+			meta: codeMeta{Highlighted: []lineRange{{First: 2, Last: 1000000000}}},
+			want: []codeLine{{Text: "a"}, {Text: "b", Highlighted: true}},
+		},
+		{
+			name: "notation comments",
+			code: "add(); // [!code ++]\nremove(); /* [!code --] */\n<p></p> <!-- [!code focus] -->\nx = 1 # [!code hl]",
+			meta: codeMeta{},
+			want: []codeLine{
+				{Text: "add();", Added: true},
+				{Text: "remove();", Removed: true},
+				{Text: "<p></p>", Focused: true},
+				{Text: "x = 1", Highlighted: true},
+			},
+		},
+		{
+			name: "diff markers",
+			code: "+added\n-removed\n kept",
+			meta: codeMeta{Diff: true},
+			want: []codeLine{
+				{Text: "added", Added: true},
+				{Text: "removed", Removed: true},
+				{Text: " kept"},
+			},
+		},
+		{
+			name: "markers outside diffs",
+			code: "-1",
+			meta: codeMeta{},
+			want: []codeLine{{Text: "-1"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := codeLines(test.code, test.meta); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("codeLines() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package markdown
+
+import (
+	"html/template"
+	"io/fs"
+	"sync"
+)
+
+// Templates holds the component partials, assigned on startup.
+var Templates fs.FS
+
+const COMPONENT_TEMPLATES = "mdx_*.tmpl"
+
+// ComponentData is passed to the template partial of a component.
+type ComponentData struct {
+	Props    map[string]interface{}
+	Children template.HTML
+}
+
+var componentsMutex sync.RWMutex
+
+// components maps an MDX tag name to the template partial that renders it.
+var components = map[string]string{
+	"Callout":     "mdx_callout",
+	"CodeSandbox": "mdx_code_sandbox",
+	"Highlight":   "mdx_highlight",
+	"ImageCard":   "mdx_image_card",
+	"Pill":        "mdx_pill",
+}
+
+// RegisterComponent makes an MDX tag render through the given template partial.
+func RegisterComponent(name string, templateName string) {
+	componentsMutex.Lock()
+	defer componentsMutex.Unlock()
+
+	components[name] = templateName
+}
+
+// IsComponent reports whether an MDX tag name has a registered partial.
+func IsComponent(name string) bool {
+	_, ok := componentTemplate(name)
+
+	return ok
+}
+
+func componentTemplate(name string) (string, bool) {
+	componentsMutex.RLock()
+	defer componentsMutex.RUnlock()
+
+	templateName, ok := components[name]
+
+	return templateName, ok
+}
+
+func parseComponentTemplates(fsys fs.FS) (*template.Template, error) {
+	if fsys == nil {
+		return template.New("components"), nil
+	}
+
+	return template.New("components").ParseFS(fsys, COMPONENT_TEMPLATES)
+}
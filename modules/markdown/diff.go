@@ -0,0 +1,33 @@
+package markdown
+
+import (
+	"bufio"
+	"bytes"
+	"html/template"
+)
+
+// DiffLine is a line of a diff between two versions of a file.
+type DiffLine struct {
+	Text    string
+	Added   bool
+	Removed bool
+}
+
+// Diff renders lines as a code block highlighted as language, added and
+// removed lines being marked the way diff code blocks of articles are.
+func Diff(language string, title string, lines []DiffLine) template.HTML {
+	meta := codeMeta{Language: language, Title: title, StartLine: 1, Diff: true}
+
+	decorated := make([]codeLine, 0, len(lines))
+	for _, line := range lines {
+		decorated = append(decorated, codeLine{Text: line.Text, Added: line.Added, Removed: line.Removed})
+	}
+
+	var buffer bytes.Buffer
+
+	w := bufio.NewWriter(&buffer)
+	writeCodeBlock(w, meta, decorated)
+	_ = w.Flush()
+
+	return template.HTML(buffer.String())
+}
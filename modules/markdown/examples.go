@@ -0,0 +1,131 @@
+package markdown
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Examples holds the code examples the articles show in code sandboxes,
+// assigned on startup.
+var Examples fs.FS
+
+// EXAMPLES_MODULE is the prefix of the modules that import an example, e.g.
+// '@/examples/navigation-shrink'.
+const EXAMPLES_MODULE = "@/examples/"
+
+// exampleFunctions are what an example module exports.
+var exampleFunctions = map[string]func(examples fs.FS, example string) Function{
+	"getFiles": exampleFiles,
+}
+
+// resolveExample binds the export of an example module, its name being the
+// directory of the example under examples.
+func resolveExample(examples fs.FS, imported string, module string) (Function, error) {
+	example := path.Clean(strings.TrimPrefix(module, EXAMPLES_MODULE))
+
+	if examples == nil || !fs.ValidPath(example) || example == "." {
+		return nil, fmt.Errorf("cannot resolve example '%s'", module)
+	}
+
+	if info, err := fs.Stat(examples, example); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("cannot resolve example '%s'", module)
+	}
+
+	function, ok := exampleFunctions[imported]
+	if !ok {
+		return nil, fmt.Errorf("example '%s' has no export %s", module, imported)
+	}
+
+	return function(examples, example), nil
+}
+
+// exampleFiles returns the getFiles function of an example, which maps the
+// path of every file of the example to its code, as sandboxes expect it, e.g.
+// {"/index.html": "<!DOCTYPE html>…"}.
+//
+// The files at the root of the example directory are its base version. Every
+// subdirectory is named after an option, and its files replace or add to them
+// when getFiles is called with the option set, e.g. getFiles({ animated: true })
+// reads animated/styles.css instead of styles.css.
+func exampleFiles(examples fs.FS, example string) Function {
+	return func(args []interface{}) (interface{}, error) {
+		var options map[string]interface{}
+
+		if len(args) > 0 {
+			var ok bool
+			if options, ok = args[0].(map[string]interface{}); !ok && args[0] != nil {
+				return nil, fmt.Errorf("expected an object of options")
+			}
+		}
+
+		entries, err := fs.ReadDir(examples, example)
+		if err != nil {
+			return nil, err
+		}
+
+		files := map[string]interface{}{}
+		var overlays []string
+
+		for _, entry := range entries {
+			if entry.IsDir() {
+				if truthy(options[entry.Name()]) {
+					overlays = append(overlays, entry.Name())
+				}
+				continue
+			}
+
+			if err := readExampleFile(examples, files, example, entry.Name()); err != nil {
+				return nil, err
+			}
+		}
+
+		sort.Strings(overlays)
+
+		for _, overlay := range overlays {
+			dir := path.Join(example, overlay)
+
+			err := fs.WalkDir(examples, dir, func(filePath string, entry fs.DirEntry, err error) error {
+				if err != nil || entry.IsDir() {
+					return err
+				}
+
+				return readExampleFile(examples, files, dir, strings.TrimPrefix(filePath, dir+"/"))
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return files, nil
+	}
+}
+
+func readExampleFile(examples fs.FS, files map[string]interface{}, dir string, name string) error {
+	code, err := fs.ReadFile(examples, path.Join(dir, name))
+	if err != nil {
+		return err
+	}
+
+	files["/"+name] = string(code)
+
+	return nil
+}
+
+// truthy reports whether a JavaScript value converts to true.
+func truthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	}
+
+	return true
+}
@@ -0,0 +1,61 @@
+package markdown
+
+import (
+	"html"
+
+	"github.com/yuin/goldmark/ast"
+	goldmarkRenderer "github.com/yuin/goldmark/renderer"
+	goldmarkHTML "github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/util"
+)
+
+// Heading is a section heading of a rendered article.
+type Heading struct {
+	Level int
+	ID    string
+	Title string
+}
+
+// headingRenderer renders headings, which get slug based IDs from the
+// parser, with a self-link anchor so every section can be deep linked.
+type headingRenderer struct{}
+
+func (r *headingRenderer) RegisterFuncs(reg goldmarkRenderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindHeading, r.renderHeading)
+}
+
+func (r *headingRenderer) renderHeading(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	heading := node.(*ast.Heading)
+	tag := "h" + string("0123456"[heading.Level])
+
+	id := ""
+	if value, ok := heading.AttributeString("id"); ok {
+		if bytes, ok := value.([]byte); ok {
+			id = string(bytes)
+		}
+	}
+
+	if entering {
+		_, _ = w.WriteString("<" + tag)
+
+		if heading.Attributes() != nil {
+			goldmarkHTML.RenderAttributes(w, node, goldmarkHTML.HeadingAttributeFilter)
+		}
+
+		if id != "" {
+			_, _ = w.WriteString(` class="title-link"`)
+		}
+
+		_ = w.WriteByte('>')
+
+		return ast.WalkContinue, nil
+	}
+
+	if id != "" {
+		_, _ = w.WriteString(`<a class="anchor" href="#` + html.EscapeString(id) + `" aria-label="Link to this section">#</a>`)
+	}
+
+	_, _ = w.WriteString("</" + tag + ">\n")
+
+	return ast.WalkContinue, nil
+}
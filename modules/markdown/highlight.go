@@ -0,0 +1,249 @@
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/yuin/goldmark/ast"
+	goldmarkRenderer "github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// Themes of the code blocks, following the light and dark modes of the site.
+const (
+	LIGHT_THEME = "github"
+	DARK_THEME  = "github-dark"
+)
+
+// TOKEN_CLASS_PREFIX keeps the token classes, e.g. "hl-k" for keywords,
+// apart from the tailwind ones.
+const TOKEN_CLASS_PREFIX = "hl-"
+
+// tokenLines splits code into lines of highlighted tokens. Languages without
+// a lexer get a single plain text token per line.
+func tokenLines(language string, code string) [][]chroma.Token {
+	var tokens []chroma.Token
+
+	if lexer := lexers.Get(language); language != "" && lexer != nil {
+		if iterator, err := chroma.Coalesce(lexer).Tokenise(nil, code); err == nil {
+			tokens = iterator.Tokens()
+		}
+	}
+
+	if tokens == nil {
+		tokens = []chroma.Token{{Type: chroma.Text, Value: code}}
+	}
+
+	lines := chroma.SplitTokensIntoLines(tokens)
+
+	// Drop the empty line after the final newline
+	if n := len(lines); n > 0 {
+		last := lines[n-1]
+		if len(last) == 0 || (len(last) == 1 && strings.TrimSpace(last[0].Value) == "") {
+			lines = lines[:n-1]
+		}
+	}
+
+	return lines
+}
+
+// tokenClass returns the CSS class of a token type, or "" for plain text.
+func tokenClass(tokenType chroma.TokenType) string {
+	// Negative types are the wrappers of the formatters, not tokens
+	if tokenType < 0 || tokenType == chroma.Text || tokenType == chroma.TextWhitespace {
+		return ""
+	}
+
+	class, ok := chroma.StandardTypes[tokenType]
+	if !ok || class == "" {
+		return ""
+	}
+
+	return TOKEN_CLASS_PREFIX + class
+}
+
+// writeTokens writes a line of tokens as class annotated spans. Empty lines
+// get a space so they keep their height in the grid of lines.
+func writeTokens(w util.BufWriter, tokens []chroma.Token) {
+	empty := true
+
+	defer func() {
+		if empty {
+			_ = w.WriteByte(' ')
+		}
+	}()
+
+	for _, token := range tokens {
+		value := strings.TrimRight(token.Value, "\n")
+		if value == "" {
+			continue
+		}
+
+		empty = false
+
+		if class := tokenClass(token.Type); class != "" {
+			fmt.Fprintf(w, `<span class="%s">%s</span>`, class, html.EscapeString(value))
+		} else {
+			_, _ = w.WriteString(html.EscapeString(value))
+		}
+	}
+}
+
+// codeBlockRenderer renders fenced code blocks with the markup of
+// rehype-pretty-code, which the site stylesheet is written against.
+type codeBlockRenderer struct{}
+
+func (r *codeBlockRenderer) RegisterFuncs(reg goldmarkRenderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindFencedCodeBlock, r.renderFencedCodeBlock)
+	reg.Register(ast.KindCodeBlock, r.renderFencedCodeBlock)
+}
+
+func (r *codeBlockRenderer) renderFencedCodeBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	var meta codeMeta
+	if fenced, ok := node.(*ast.FencedCodeBlock); ok && fenced.Info != nil {
+		meta = parseCodeMeta(string(fenced.Info.Segment.Value(source)))
+	} else {
+		meta = parseCodeMeta("")
+	}
+
+	var code strings.Builder
+
+	segments := node.Lines()
+	for i := 0; i < segments.Len(); i++ {
+		segment := segments.At(i)
+		code.Write(segment.Value(source))
+	}
+
+	writeCodeBlock(w, meta, codeLines(code.String(), meta))
+
+	return ast.WalkSkipChildren, nil
+}
+
+// writeCodeBlock writes the figure of a code block, its lines highlighted
+// as the language of meta.
+func writeCodeBlock(w util.BufWriter, meta codeMeta, lines []codeLine) {
+	texts := make([]string, len(lines))
+	focused := false
+
+	for i, line := range lines {
+		texts[i] = line.Text
+		focused = focused || line.Focused
+	}
+
+	tokens := tokenLines(meta.Language, strings.Join(texts, "\n"))
+
+	language := html.EscapeString(meta.Language)
+	attributes := fmt.Sprintf(`data-language="%s" data-theme="light dark"`, language)
+
+	_, _ = w.WriteString(`<figure data-rehype-pretty-code-figure="">`)
+
+	if meta.Title != "" {
+		fmt.Fprintf(w, `<figcaption data-rehype-pretty-code-title="" %s>%s</figcaption>`, attributes, html.EscapeString(meta.Title))
+	}
+
+	preAttributes := attributes
+	if focused {
+		preAttributes += ` class="has-focused"`
+	}
+
+	codeAttributes := attributes
+	if meta.LineNumbers {
+		digits := len(fmt.Sprint(meta.StartLine + len(lines) - 1))
+		codeAttributes += fmt.Sprintf(` data-line-numbers="" data-line-numbers-max-digits="%d"`, digits)
+
+		if meta.StartLine > 1 {
+			codeAttributes += fmt.Sprintf(` style="counter-set: line %d"`, meta.StartLine-1)
+		}
+	}
+
+	fmt.Fprintf(w, `<pre tabindex="0" %s><code %s>`, preAttributes, codeAttributes)
+
+	for i, line := range lines {
+		if i > 0 {
+			_ = w.WriteByte('\n')
+		}
+
+		fmt.Fprintf(w, `<span %s>`, line.attributes())
+
+		if i < len(tokens) {
+			writeTokens(w, tokens[i])
+		} else {
+			writeTokens(w, []chroma.Token{{Type: chroma.Text, Value: line.Text}})
+		}
+
+		_, _ = w.WriteString(`</span>`)
+	}
+
+	_, _ = w.WriteString(`</code></pre>`)
+	_, _ = w.WriteString(`<button type="button" class="copy-code" data-copy-code="" aria-label="Copy code">Copy</button>`)
+	_, _ = w.WriteString("</figure>\n")
+}
+
+var (
+	highlightCSS     []byte
+	highlightCSSOnce sync.Once
+)
+
+// themeColour returns the colour of a token type in style as a CSS value,
+// or fallback when the theme leaves it unset.
+func themeColour(style *chroma.Style, tokenType chroma.TokenType, fallback string) string {
+	if entry := style.Get(tokenType); entry.Colour.IsSet() {
+		return entry.Colour.String()
+	}
+
+	return fallback
+}
+
+// HighlightCSS returns the stylesheet of the token classes. Every class sets
+// the --shiki-light and --shiki-dark colours the site stylesheet switches
+// between depending on the theme.
+func HighlightCSS() []byte {
+	highlightCSSOnce.Do(func() {
+		light := styles.Get(LIGHT_THEME)
+		dark := styles.Get(DARK_THEME)
+
+		var sb strings.Builder
+
+		fmt.Fprintf(&sb, "code[data-theme] {\n  --shiki-light: %s;\n  --shiki-dark: %s;\n}\n",
+			themeColour(light, chroma.Text, "currentColor"), themeColour(dark, chroma.Text, "currentColor"))
+
+		var rules []string
+
+		for tokenType := range chroma.StandardTypes {
+			class := tokenClass(tokenType)
+			if class == "" {
+				continue
+			}
+
+			rule := fmt.Sprintf(".%s {\n  --shiki-light: %s;\n  --shiki-dark: %s;\n", class, themeColour(light, tokenType, "inherit"), themeColour(dark, tokenType, "inherit"))
+
+			if entry := light.Get(tokenType); entry.Italic == chroma.Yes {
+				rule += "  font-style: italic;\n"
+			}
+
+			if entry := light.Get(tokenType); entry.Bold == chroma.Yes {
+				rule += "  font-weight: bold;\n"
+			}
+
+			rules = append(rules, rule+"}\n")
+		}
+
+		sort.Strings(rules)
+
+		sb.WriteString(strings.Join(rules, ""))
+
+		highlightCSS = []byte(sb.String())
+	})
+
+	return highlightCSS
+}
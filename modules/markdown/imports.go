@@ -0,0 +1,180 @@
+package markdown
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// StaticAssets holds the static files asset imports are resolved against.
+var StaticAssets fs.FS
+
+// ASSETS_DIR is where the imported assets live among the static files.
+const ASSETS_DIR = "assets"
+
+var importPattern = regexp.MustCompile(`^import\s+(?:(.+?)\s+from\s+)?['"]([^'"]+)['"];?\s*$`)
+
+var assetExtensions = map[string]bool{
+	".avif": true,
+	".gif":  true,
+	".jpeg": true,
+	".jpg":  true,
+	".png":  true,
+	".svg":  true,
+	".webp": true,
+}
+
+// importBinding is a single name introduced by an import statement.
+type importBinding struct {
+	imported string // name exported by the module, "default" for default imports
+	local    string // name the article uses
+}
+
+// parseImportClause splits `image01`, `{ Callout, Pill as Tag }` or
+// `Default, { Named }` into its bindings.
+func parseImportClause(clause string) []importBinding {
+	var bindings []importBinding
+
+	clause = strings.TrimSpace(clause)
+
+	if named := strings.Index(clause, "{"); named >= 0 {
+		end := strings.LastIndex(clause, "}")
+		if end < named {
+			end = len(clause)
+		}
+
+		for _, specifier := range strings.Split(clause[named+1:end], ",") {
+			fields := strings.Fields(specifier)
+
+			switch {
+			case len(fields) == 1:
+				bindings = append(bindings, importBinding{imported: fields[0], local: fields[0]})
+			case len(fields) == 3 && fields[1] == "as":
+				bindings = append(bindings, importBinding{imported: fields[0], local: fields[2]})
+			}
+		}
+
+		clause = strings.TrimSuffix(strings.TrimSpace(clause[:named]), ",")
+	}
+
+	if fields := strings.Fields(clause); len(fields) == 3 && fields[0] == "*" && fields[1] == "as" {
+		bindings = append(bindings, importBinding{imported: "*", local: fields[2]})
+	} else if clause != "" {
+		bindings = append(bindings, importBinding{imported: "default", local: clause})
+	}
+
+	return bindings
+}
+
+// resolveAsset maps a Next.js style asset import such as
+// '/public/images/post.png' to its URL under /static/assets, if static has it.
+func resolveAsset(static fs.FS, module string) (string, bool) {
+	if !assetExtensions[strings.ToLower(path.Ext(module))] {
+		return "", false
+	}
+
+	relative := module
+	for _, prefix := range []string{"@/public/", "/public/", "public/", "/static/assets/", "/"} {
+		if strings.HasPrefix(relative, prefix) {
+			relative = strings.TrimPrefix(relative, prefix)
+			break
+		}
+	}
+
+	assetPath := path.Join(ASSETS_DIR, relative)
+
+	if static == nil || !strings.HasPrefix(assetPath, ASSETS_DIR+"/") {
+		return "", false
+	}
+
+	if _, err := fs.Stat(static, assetPath); err != nil {
+		return "", false
+	}
+
+	return "/static/" + assetPath, true
+}
+
+// resolveImports blanks out the import statements of source, keeping line
+// numbers intact, and binds every imported name in the renderer scope.
+// Component imports are satisfied by the component registry, asset imports
+// resolve to their static URL and example imports to their functions.
+func (r *renderer) resolveImports(source []byte, line int) ([]byte, error) {
+	lines := bytes.Split(source, []byte("\n"))
+	fence := ""
+
+	var errs []error
+
+	for i, content := range lines {
+		trimmed := strings.TrimSpace(string(content))
+
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+
+		if !strings.HasPrefix(trimmed, "import ") {
+			continue
+		}
+
+		lines[i] = nil
+
+		match := importPattern.FindStringSubmatch(trimmed)
+		if match == nil {
+			errs = append(errs, r.errorf(line+i, "invalid import statement %q", trimmed))
+			continue
+		}
+
+		module := match[2]
+
+		for _, binding := range parseImportClause(match[1]) {
+			if err := r.bindImport(binding, module); err != nil {
+				errs = append(errs, r.errorf(line+i, "%v", err))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return bytes.Join(lines, []byte("\n")), nil
+}
+
+func (r *renderer) bindImport(binding importBinding, module string) error {
+	if url, ok := resolveAsset(r.static, module); ok && binding.imported == "default" {
+		r.scope[binding.local] = url
+		return nil
+	}
+
+	if assetExtensions[strings.ToLower(path.Ext(module))] {
+		return fmt.Errorf("cannot resolve asset '%s' under %s", module, "/static/"+ASSETS_DIR)
+	}
+
+	if strings.HasPrefix(module, EXAMPLES_MODULE) {
+		function, err := resolveExample(r.examples, binding.imported, module)
+		if err != nil {
+			return err
+		}
+
+		r.scope[binding.local] = function
+		return nil
+	}
+
+	if IsComponent(binding.imported) {
+		r.aliases[binding.local] = binding.imported
+		return nil
+	}
+
+	return fmt.Errorf("cannot resolve import of %s from '%s'", binding.local, module)
+}
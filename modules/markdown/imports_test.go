@@ -0,0 +1,209 @@
+package markdown
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseImportClause(t *testing.T) {
+	tests := []struct {
+		clause string
+		want   []importBinding
+	}{
+		{clause: "image01", want: []importBinding{{imported: "default", local: "image01"}}},
+		{clause: "{ Callout }", want: []importBinding{{imported: "Callout", local: "Callout"}}},
+		{
+			clause: "{ Callout, Pill as Tag }",
+			want: []importBinding{
+				{imported: "Callout", local: "Callout"},
+				{imported: "Pill", local: "Tag"},
+			},
+		},
+		{
+			clause: "Default, { Named }",
+			want: []importBinding{
+				{imported: "Named", local: "Named"},
+				{imported: "default", local: "Default"},
+			},
+		},
+		{clause: "* as examples", want: []importBinding{{imported: "*", local: "examples"}}},
+		{clause: "{ getFiles, }", want: []importBinding{{imported: "getFiles", local: "getFiles"}}},
+		{clause: "", want: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.clause, func(t *testing.T) {
+			if got := parseImportClause(test.clause); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("parseImportClause(%q) = %v, want %v", test.clause, got, test.want)
+			}
+		})
+	}
+}
+
+var testStatic = fstest.MapFS{
+	"assets/images/post_2_1.png": {Data: []byte("png")},
+}
+
+var testExamples = fstest.MapFS{
+	"navigation-shrink/index.html":                  {Data: []byte("<nav></nav>")},
+	"navigation-shrink/styles.css":                  {Data: []byte("nav {}")},
+	"navigation-shrink/animationStyles/styles.css":  {Data: []byte("nav { animation: shrink; }")},
+	"navigation-shrink/animationStyles/extra/a.css": {Data: []byte("a {}")},
+}
+
+func TestResolveAsset(t *testing.T) {
+	tests := []struct {
+		module string
+		want   string
+		ok     bool
+	}{
+		{module: "/public/images/post_2_1.png", want: "/static/assets/images/post_2_1.png", ok: true},
+		{module: "@/public/images/post_2_1.png", want: "/static/assets/images/post_2_1.png", ok: true},
+		{module: "public/images/post_2_1.png", want: "/static/assets/images/post_2_1.png", ok: true},
+		{module: "/static/assets/images/post_2_1.png", want: "/static/assets/images/post_2_1.png", ok: true},
+		{module: "/images/post_2_1.PNG", ok: false},
+		{module: "/public/images/missing.png", ok: false},
+		{module: "/public/../../secret.png", ok: false},
+		{module: "@/components/pill", ok: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.module, func(t *testing.T) {
+			got, ok := resolveAsset(testStatic, test.module)
+
+			if got != test.want || ok != test.ok {
+				t.Errorf("resolveAsset(%q) = %q, %v, want %q, %v", test.module, got, ok, test.want, test.ok)
+			}
+		})
+	}
+}
+
+func TestResolveImports(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		scope   []string
+		aliases map[string]string
+		wantErr string
+	}{
+		{
+			// The imports of the navbar article
+			name: "components, examples and assets",
+			source: strings.Join([]string{
+				"import { Callout } from '@/lib/ui/callout';",
+				"import { Pill as Tag } from '@/components/pill';",
+				"import { getFiles } from '@/examples/navigation-shrink';",
+				"import image01 from '/public/images/post_2_1.png'",
+			}, "\n"),
+			scope:   []string{"getFiles", "image01"},
+			aliases: map[string]string{"Callout": "Callout", "Tag": "Pill"},
+		},
+		{
+			name:    "imports in code fences",
+			source:  "```js\nimport { nothing } from 'anywhere';\n```",
+			aliases: map[string]string{},
+		},
+		{name: "missing asset", source: "import image from '/public/images/missing.png'", wantErr: "cannot resolve asset"},
+		{name: "missing example", source: "import { getFiles } from '@/examples/missing'", wantErr: "cannot resolve example"},
+		{name: "unknown example export", source: "import { getCode } from '@/examples/navigation-shrink'", wantErr: "has no export getCode"},
+		{name: "unknown module", source: "import { Chart } from '@/components/chart'", wantErr: "cannot resolve import of Chart"},
+		{name: "invalid statement", source: "import {", wantErr: "invalid import statement"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := &renderer{
+				path:     "article.mdx",
+				static:   testStatic,
+				examples: testExamples,
+				scope:    map[string]interface{}{},
+				aliases:  map[string]string{},
+			}
+
+			body, err := r.resolveImports([]byte(test.source), 1)
+
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("resolveImports() error = %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("resolveImports() error = %v", err)
+			}
+
+			// Import statements are blanked out keeping the line numbers,
+			// those in code fences are left alone
+			if lines := strings.Count(string(body), "\n"); lines != strings.Count(test.source, "\n") {
+				t.Errorf("resolveImports() changed the number of lines to %d", lines)
+			}
+
+			if strings.Contains(string(body), "import") != strings.Contains(test.source, "```") {
+				t.Errorf("resolveImports() = %q", body)
+			}
+
+			for _, name := range test.scope {
+				if _, ok := r.scope[name]; !ok {
+					t.Errorf("resolveImports() didn't bind %s", name)
+				}
+			}
+
+			if !reflect.DeepEqual(r.aliases, test.aliases) {
+				t.Errorf("resolveImports() aliases = %v, want %v", r.aliases, test.aliases)
+			}
+		})
+	}
+}
+
+func TestExampleFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		args []interface{}
+		want map[string]interface{}
+	}{
+		{
+			name: "base version",
+			args: nil,
+			want: map[string]interface{}{"/index.html": "<nav></nav>", "/styles.css": "nav {}"},
+		},
+		{
+			name: "option off",
+			args: []interface{}{map[string]interface{}{"animationStyles": false}},
+			want: map[string]interface{}{"/index.html": "<nav></nav>", "/styles.css": "nav {}"},
+		},
+		{
+			name: "option on",
+			args: []interface{}{map[string]interface{}{"animationStyles": true}},
+			want: map[string]interface{}{
+				"/index.html":  "<nav></nav>",
+				"/styles.css":  "nav { animation: shrink; }",
+				"/extra/a.css": "a {}",
+			},
+		},
+	}
+
+	getFiles, err := resolveExample(testExamples, "getFiles", "@/examples/navigation-shrink")
+	if err != nil {
+		t.Fatalf("resolveExample() error = %v", err)
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := getFiles(test.args)
+			if err != nil {
+				t.Fatalf("getFiles() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("getFiles() = %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	if _, err := getFiles([]interface{}{"animationStyles"}); err == nil {
+		t.Errorf("getFiles() with a string of options succeeded")
+	}
+}
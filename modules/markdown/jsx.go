@@ -0,0 +1,369 @@
+package markdown
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// jsxParser evaluates the subset of JSX props and JavaScript literals used in
+// the articles: strings, numbers, booleans, null, objects, arrays,
+// identifiers resolved against the document scope and calls of the functions
+// in it.
+type jsxParser struct {
+	src   string
+	pos   int
+	scope map[string]interface{}
+}
+
+func isIdentifierStart(r byte) bool {
+	return r == '_' || r == '$' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+func isIdentifierPart(r byte) bool {
+	return isIdentifierStart(r) || (r >= '0' && r <= '9')
+}
+
+func (p *jsxParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *jsxParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+
+	return p.src[p.pos]
+}
+
+func (p *jsxParser) skipSpaces() {
+	for !p.eof() && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *jsxParser) identifier() string {
+	start := p.pos
+
+	for !p.eof() && isIdentifierPart(p.src[p.pos]) {
+		p.pos++
+	}
+
+	return p.src[start:p.pos]
+}
+
+// attributeName reads a prop name, which unlike identifiers may contain
+// dashes and colons, e.g. aria-label.
+func (p *jsxParser) attributeName() string {
+	start := p.pos
+
+	for !p.eof() && (isIdentifierPart(p.src[p.pos]) || p.src[p.pos] == '-' || p.src[p.pos] == ':') {
+		p.pos++
+	}
+
+	return p.src[start:p.pos]
+}
+
+func (p *jsxParser) expect(c byte) error {
+	p.skipSpaces()
+
+	if p.peek() != c {
+		return fmt.Errorf("expected %q at %q", c, p.rest())
+	}
+
+	p.pos++
+
+	return nil
+}
+
+func (p *jsxParser) rest() string {
+	rest := p.src[p.pos:]
+
+	if len(rest) > 20 {
+		rest = rest[:20] + "…"
+	}
+
+	return rest
+}
+
+// props parses tag attributes until the end of the opening tag and reports
+// whether the tag is self-closing.
+func (p *jsxParser) props() (map[string]interface{}, bool, error) {
+	props := map[string]interface{}{}
+
+	for {
+		p.skipSpaces()
+
+		switch {
+		case p.eof():
+			return nil, false, fmt.Errorf("unterminated tag")
+		case p.peek() == '>':
+			p.pos++
+			return props, false, nil
+		case strings.HasPrefix(p.src[p.pos:], "/>"):
+			p.pos += 2
+			return props, true, nil
+		case !isIdentifierStart(p.peek()):
+			return nil, false, fmt.Errorf("unexpected %q in tag", p.rest())
+		}
+
+		name := p.attributeName()
+
+		p.skipSpaces()
+
+		if p.peek() != '=' {
+			props[name] = true
+			continue
+		}
+
+		p.pos++
+		p.skipSpaces()
+
+		var value interface{}
+		var err error
+
+		switch p.peek() {
+		case '"', '\'':
+			value, err = p.string()
+		case '{':
+			p.pos++
+			value, err = p.value()
+			if err == nil {
+				err = p.expect('}')
+			}
+		default:
+			err = fmt.Errorf("invalid value for prop %q", name)
+		}
+
+		if err != nil {
+			return nil, false, fmt.Errorf("prop %q: %w", name, err)
+		}
+
+		props[name] = value
+	}
+}
+
+func (p *jsxParser) value() (interface{}, error) {
+	p.skipSpaces()
+
+	c := p.peek()
+
+	switch {
+	case c == '"' || c == '\'' || c == '`':
+		return p.string()
+	case c == '{':
+		return p.object()
+	case c == '[':
+		return p.array()
+	case c == '-' || (c >= '0' && c <= '9'):
+		return p.number()
+	case isIdentifierStart(c):
+		start := p.pos
+		name := p.identifier()
+
+		switch name {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null", "undefined":
+			return nil, nil
+		}
+
+		value, ok := p.scope[name]
+		if !ok {
+			p.pos = start
+			return nil, fmt.Errorf("%s is not defined", name)
+		}
+
+		p.skipSpaces()
+
+		if p.peek() == '(' {
+			return p.call(name, value)
+		}
+
+		return value, nil
+	case c == 0:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+
+	return nil, fmt.Errorf("unsupported expression %q", p.rest())
+}
+
+// Function is a JavaScript function the props can call, e.g. one imported
+// from an example module.
+type Function func(args []interface{}) (interface{}, error)
+
+// call evaluates the arguments of a call to the function bound to name and
+// calls it.
+func (p *jsxParser) call(name string, value interface{}) (interface{}, error) {
+	function, ok := value.(Function)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a function", name)
+	}
+
+	p.pos++
+
+	var args []interface{}
+
+	for {
+		p.skipSpaces()
+
+		if p.peek() == ')' {
+			p.pos++
+			break
+		}
+
+		arg, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, arg)
+
+		p.skipSpaces()
+
+		if p.peek() == ',' {
+			p.pos++
+		} else if p.peek() != ')' {
+			return nil, fmt.Errorf("expected ',' or ')' at %q", p.rest())
+		}
+	}
+
+	result, err := function(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s(…): %w", name, err)
+	}
+
+	return result, nil
+}
+
+func (p *jsxParser) string() (string, error) {
+	quote := p.src[p.pos]
+	p.pos++
+
+	var sb strings.Builder
+
+	for !p.eof() {
+		c := p.src[p.pos]
+		p.pos++
+
+		switch {
+		case c == quote:
+			return sb.String(), nil
+		case c == '\\' && !p.eof():
+			escaped := p.src[p.pos]
+			p.pos++
+
+			switch escaped {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			default:
+				sb.WriteByte(escaped)
+			}
+		case quote == '`' && c == '$' && p.peek() == '{':
+			return "", fmt.Errorf("template literal interpolation is not supported")
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return "", fmt.Errorf("unterminated string")
+}
+
+func (p *jsxParser) number() (float64, error) {
+	start := p.pos
+
+	if p.peek() == '-' {
+		p.pos++
+	}
+
+	for !p.eof() && (p.src[p.pos] == '.' || (p.src[p.pos] >= '0' && p.src[p.pos] <= '9')) {
+		p.pos++
+	}
+
+	return strconv.ParseFloat(p.src[start:p.pos], 64)
+}
+
+func (p *jsxParser) object() (map[string]interface{}, error) {
+	p.pos++
+
+	object := map[string]interface{}{}
+
+	for {
+		p.skipSpaces()
+
+		if p.peek() == '}' {
+			p.pos++
+			return object, nil
+		}
+
+		var key string
+		var err error
+
+		switch c := p.peek(); {
+		case c == '"' || c == '\'':
+			key, err = p.string()
+		case isIdentifierStart(c):
+			key = p.identifier()
+		default:
+			err = fmt.Errorf("invalid object key at %q", p.rest())
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect(':'); err != nil {
+			return nil, err
+		}
+
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+
+		object[key] = value
+
+		p.skipSpaces()
+
+		if p.peek() == ',' {
+			p.pos++
+		} else if p.peek() != '}' {
+			return nil, fmt.Errorf("expected ',' or '}' at %q", p.rest())
+		}
+	}
+}
+
+func (p *jsxParser) array() ([]interface{}, error) {
+	p.pos++
+
+	array := []interface{}{}
+
+	for {
+		p.skipSpaces()
+
+		if p.peek() == ']' {
+			p.pos++
+			return array, nil
+		}
+
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+
+		array = append(array, value)
+
+		p.skipSpaces()
+
+		if p.peek() == ',' {
+			p.pos++
+		} else if p.peek() != ']' {
+			return nil, fmt.Errorf("expected ',' or ']' at %q", p.rest())
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package markdown
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestProps(t *testing.T) {
+	// getFiles echoes its options, standing in for the example functions
+	getFiles := Function(func(args []interface{}) (interface{}, error) {
+		return map[string]interface{}{"/args": args}, nil
+	})
+
+	failing := Function(func(args []interface{}) (interface{}, error) {
+		return nil, errors.New("broken")
+	})
+
+	scope := map[string]interface{}{
+		"image01":  "/static/assets/images/post_2_1.png",
+		"getFiles": getFiles,
+		"failing":  failing,
+	}
+
+	tests := []struct {
+		name        string
+		src         string
+		want        map[string]interface{}
+		selfClosing bool
+		wantErr     bool
+	}{
+		{
+			name: "string props",
+			src:  ` title="Warning! Experimental feature" type='warning'>`,
+			want: map[string]interface{}{"title": "Warning! Experimental feature", "type": "warning"},
+		},
+		{
+			name:        "boolean shorthand and dashed names",
+			src:         ` open aria-label="Close" />`,
+			want:        map[string]interface{}{"open": true, "aria-label": "Close"},
+			selfClosing: true,
+		},
+		{
+			name: "literals",
+			src:  ` count={3} ratio={-0.5} on={true} off={false} none={null} missing={undefined} text={"a\"b"} raw={` + "`x`" + `}>`,
+			want: map[string]interface{}{
+				"count":   3.0,
+				"ratio":   -0.5,
+				"on":      true,
+				"off":     false,
+				"none":    nil,
+				"missing": nil,
+				"text":    `a"b`,
+				"raw":     "x",
+			},
+		},
+		{
+			name: "objects and arrays",
+			src:  ` data={{ a: 1, 'b': [1, "two", { c: true }], }} />`,
+			want: map[string]interface{}{
+				"data": map[string]interface{}{
+					"a": 1.0,
+					"b": []interface{}{1.0, "two", map[string]interface{}{"c": true}},
+				},
+			},
+			selfClosing: true,
+		},
+		{
+			name: "identifier from an asset import",
+			src:  ` src={image01} alt="Navbar" />`,
+			want: map[string]interface{}{
+				"src": "/static/assets/images/post_2_1.png",
+				"alt": "Navbar",
+			},
+			selfClosing: true,
+		},
+		{
+			// The shape of the sandboxes of the navbar article
+			name: "call spanning lines",
+			src:  " files={\n  getFiles({ animationStyles: false})\n} />",
+			want: map[string]interface{}{
+				"files": map[string]interface{}{
+					"/args": []interface{}{map[string]interface{}{"animationStyles": false}},
+				},
+			},
+			selfClosing: true,
+		},
+		{
+			name: "call with several arguments",
+			src:  ` files={getFiles( "a" , 2 )}>`,
+			want: map[string]interface{}{
+				"files": map[string]interface{}{"/args": []interface{}{"a", 2.0}},
+			},
+		},
+		{
+			name: "call without arguments",
+			src:  ` files={getFiles()}>`,
+			want: map[string]interface{}{
+				"files": map[string]interface{}{"/args": []interface{}(nil)},
+			},
+		},
+		{name: "undefined identifier", src: ` src={image02} />`, wantErr: true},
+		{name: "call of a value", src: ` src={image01()} />`, wantErr: true},
+		{name: "failing call", src: ` files={failing()} />`, wantErr: true},
+		{name: "unterminated call", src: ` files={getFiles({}} />`, wantErr: true},
+		{name: "template interpolation", src: " text={`${a}`} />", wantErr: true},
+		{name: "unterminated string", src: ` title="open`, wantErr: true},
+		{name: "unterminated tag", src: ` title="open"`, wantErr: true},
+		{name: "unquoted value", src: ` title=open />`, wantErr: true},
+		{name: "missing closing brace", src: ` data={{ a: 1 } />`, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := &jsxParser{src: test.src, scope: scope}
+
+			props, selfClosing, err := p.props()
+
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("props() = %v, want an error", props)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("props() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(props, test.want) {
+				t.Errorf("props() = %#v, want %#v", props, test.want)
+			}
+
+			if selfClosing != test.selfClosing {
+				t.Errorf("props() self-closing = %v, want %v", selfClosing, test.selfClosing)
+			}
+		})
+	}
+}
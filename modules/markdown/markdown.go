@@ -0,0 +1,150 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io/fs"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	goldmarkRenderer "github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/util"
+)
+
+// Source is an article body along with where it comes from, so errors can
+// point at the offending file and line.
+type Source struct {
+	Path string
+	Line int // line of Path where Body starts
+	Body []byte
+
+	// Component partials, static files and code examples of the copy of the
+	// content the body belongs to, Templates, StaticAssets and Examples when nil
+	Templates    fs.FS
+	StaticAssets fs.FS
+	Examples     fs.FS
+}
+
+// Document holds the output of rendering an article body.
+type Document struct {
+	HTML     template.HTML
+	Text     string    // plain text without code blocks
+	Headings []Heading // every heading, in order
+	Excerpt  string    // text of the first paragraph
+}
+
+// Error is a rendering problem located in an article source.
+type Error struct {
+	Path    string
+	Line    int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.Path, e.Line, e.Message)
+}
+
+var md = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	goldmark.WithRendererOptions(
+		html.WithUnsafe(),
+		goldmarkRenderer.WithNodeRenderers(
+			util.Prioritized(&codeBlockRenderer{}, 100),
+			util.Prioritized(&headingRenderer{}, 100),
+		),
+	),
+)
+
+type renderer struct {
+	path      string
+	templates *template.Template
+	static    fs.FS
+	examples  fs.FS
+	scope     map[string]interface{}
+	aliases   map[string]string
+}
+
+func (r *renderer) errorf(line int, format string, args ...interface{}) error {
+	return &Error{Path: r.path, Line: line, Message: fmt.Sprintf(format, args...)}
+}
+
+// render expands the MDX components of source, which starts at the given
+// line, and converts the result to HTML.
+func (r *renderer) render(source []byte, line int) (string, error) {
+	expanded, fragments, err := r.expandComponents(source, line)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+
+	if err := md.Convert(expanded, &buf); err != nil {
+		return "", r.errorf(line, "%v", err)
+	}
+
+	return substituteFragments(buf.String(), fragments), nil
+}
+
+// Render converts an MDX article body into HTML.
+func Render(source Source) (*Document, error) {
+	templatesFS, static, examples := source.Templates, source.StaticAssets, source.Examples
+	if templatesFS == nil {
+		templatesFS = Templates
+	}
+
+	if static == nil {
+		static = StaticAssets
+	}
+
+	if examples == nil {
+		examples = Examples
+	}
+
+	templates, err := parseComponentTemplates(templatesFS)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &renderer{
+		path:      source.Path,
+		templates: templates,
+		static:    static,
+		examples:  examples,
+		scope:     map[string]interface{}{},
+		aliases:   map[string]string{},
+	}
+
+	body, err := r.resolveImports(source.Body, source.Line)
+	if err != nil {
+		return nil, err
+	}
+
+	html, err := r.render(body, source.Line)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Document{
+		HTML:     template.HTML(html),
+		Text:     plainText(html),
+		Headings: headings(html),
+		Excerpt:  excerpt(html),
+	}, nil
+}
+
+// RenderMarkdown converts an MDX article body into HTML without resolving
+// its imports or expanding its components, which are left as written. It
+// lets the links of an article Render fails on be checked anyway.
+func RenderMarkdown(body []byte) template.HTML {
+	var buf bytes.Buffer
+
+	if err := md.Convert(body, &buf); err != nil {
+		return ""
+	}
+
+	return template.HTML(buf.String())
+}
@@ -0,0 +1,281 @@
+package markdown
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// fragment is the rendered HTML of a component, substituted back into the
+// Markdown output through its placeholder.
+type fragment struct {
+	placeholder string
+	html        string
+	block       bool
+}
+
+func placeholder(index int) string {
+	return fmt.Sprintf("MDXCOMPONENT%dX", index)
+}
+
+// isLineStart reports whether only indentation precedes pos on its line.
+func isLineStart(source []byte, pos int) bool {
+	for i := pos - 1; i >= 0 && source[i] != '\n'; i-- {
+		if source[i] != ' ' && source[i] != '\t' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isLineEnd reports whether only whitespace follows pos on its line.
+func isLineEnd(source []byte, pos int) bool {
+	for i := pos; i < len(source) && source[i] != '\n'; i++ {
+		if source[i] != ' ' && source[i] != '\t' && source[i] != '\r' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// fenceAt returns the fence marker when a fenced code block opens at pos.
+func fenceAt(source []byte, pos int) string {
+	if !isLineStart(source, pos) {
+		return ""
+	}
+
+	for _, marker := range []string{"```", "~~~"} {
+		if bytes.HasPrefix(source[pos:], []byte(marker)) {
+			end := pos
+			for end < len(source) && source[end] == marker[0] {
+				end++
+			}
+
+			return string(source[pos:end])
+		}
+	}
+
+	return ""
+}
+
+// skipFence returns the position after the fenced code block opened at pos.
+func skipFence(source []byte, pos int, fence string) int {
+	lineEnd := bytes.IndexByte(source[pos:], '\n')
+	if lineEnd < 0 {
+		return len(source)
+	}
+
+	for i := pos + lineEnd + 1; i < len(source); {
+		next := bytes.IndexByte(source[i:], '\n')
+		line := source[i:]
+		if next >= 0 {
+			line = source[i : i+next]
+		}
+
+		if strings.HasPrefix(strings.TrimLeft(string(line), " \t"), fence) {
+			if next < 0 {
+				return len(source)
+			}
+			return i + next + 1
+		}
+
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+
+	return len(source)
+}
+
+// skipCodeSpan returns the position after the inline code span opened at pos.
+func skipCodeSpan(source []byte, pos int) int {
+	end := pos
+	for end < len(source) && source[end] == '`' {
+		end++
+	}
+
+	ticks := source[pos:end]
+
+	if closing := bytes.Index(source[end:], ticks); closing >= 0 {
+		return end + closing + len(ticks)
+	}
+
+	return end
+}
+
+// findClosingTag returns the start and end of the tag closing name, taking
+// nested elements with the same name into account.
+func findClosingTag(source []byte, pos int, name string) (int, int) {
+	opening := []byte("<" + name)
+	closing := []byte("</" + name + ">")
+	depth := 0
+
+	for i := pos; i < len(source); i++ {
+		switch {
+		case bytes.HasPrefix(source[i:], closing):
+			if depth == 0 {
+				return i, i + len(closing)
+			}
+			depth--
+			i += len(closing) - 1
+		case bytes.HasPrefix(source[i:], opening) && i+len(opening) < len(source):
+			next := source[i+len(opening)]
+			if next == '>' || next == ' ' || next == '\n' || next == '\t' {
+				depth++
+			}
+		}
+	}
+
+	return -1, -1
+}
+
+// expandComponents replaces every MDX component in source with a placeholder
+// and renders it through its template partial.
+func (r *renderer) expandComponents(source []byte, line int) ([]byte, []fragment, error) {
+	var out bytes.Buffer
+	var fragments []fragment
+	var errs []error
+
+	lineAt := func(pos int) int {
+		return line + bytes.Count(source[:pos], []byte("\n"))
+	}
+
+	for pos := 0; pos < len(source); {
+		c := source[pos]
+
+		if fence := fenceAt(source, pos); fence != "" {
+			end := skipFence(source, pos, fence)
+			out.Write(source[pos:end])
+			pos = end
+			continue
+		}
+
+		if c == '`' {
+			end := skipCodeSpan(source, pos)
+			out.Write(source[pos:end])
+			pos = end
+			continue
+		}
+
+		if c != '<' || pos+1 >= len(source) || source[pos+1] < 'A' || source[pos+1] > 'Z' {
+			out.WriteByte(c)
+			pos++
+			continue
+		}
+
+		html, end, err := r.renderComponent(source, pos, lineAt)
+		if err != nil {
+			errs = append(errs, err)
+			out.WriteByte(c)
+			pos++
+			continue
+		}
+
+		block := isLineStart(source, pos) && isLineEnd(source, end)
+		token := placeholder(len(fragments))
+
+		fragments = append(fragments, fragment{
+			placeholder: token,
+			html:        html,
+			block:       block,
+		})
+
+		if block {
+			out.WriteString("\n" + token + "\n")
+		} else {
+			out.WriteString(token)
+		}
+
+		pos = end
+	}
+
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+
+	return out.Bytes(), fragments, nil
+}
+
+// renderComponent renders the component whose opening tag starts at pos and
+// returns its HTML along with the position right after its closing tag.
+func (r *renderer) renderComponent(source []byte, pos int, lineAt func(int) int) (string, int, error) {
+	parser := &jsxParser{src: string(source), pos: pos + 1, scope: r.scope}
+	name := parser.identifier()
+
+	component := name
+	if imported, ok := r.aliases[name]; ok {
+		component = imported
+	}
+
+	templateName, ok := componentTemplate(component)
+	if !ok {
+		return "", 0, r.errorf(lineAt(pos), "unknown MDX component <%s>", name)
+	}
+
+	props, selfClosing, err := parser.props()
+	if err != nil {
+		return "", 0, r.errorf(lineAt(pos), "<%s>: %v", name, err)
+	}
+
+	end := parser.pos
+	data := ComponentData{Props: props}
+
+	if !selfClosing {
+		closeStart, closeEnd := findClosingTag(source, end, name)
+		if closeStart < 0 {
+			return "", 0, r.errorf(lineAt(pos), "<%s> is never closed", name)
+		}
+
+		children := source[end:closeStart]
+		block := bytes.ContainsRune(bytes.TrimSpace(children), '\n')
+
+		html, err := r.render(children, lineAt(end))
+		if err != nil {
+			return "", 0, err
+		}
+
+		if !block {
+			html = unwrapParagraph(html)
+		}
+
+		data.Children = template.HTML(html)
+		end = closeEnd
+	}
+
+	var buf bytes.Buffer
+
+	if err := r.templates.ExecuteTemplate(&buf, templateName, data); err != nil {
+		return "", 0, r.errorf(lineAt(pos), "<%s>: %v", name, err)
+	}
+
+	return buf.String(), end, nil
+}
+
+// substituteFragments puts the rendered components back into the HTML output.
+func substituteFragments(html string, fragments []fragment) string {
+	for _, f := range fragments {
+		if f.block {
+			html = strings.Replace(html, "<p>"+f.placeholder+"</p>", f.html, 1)
+		}
+
+		html = strings.Replace(html, f.placeholder, f.html, 1)
+	}
+
+	return html
+}
+
+// unwrapParagraph strips the paragraph Markdown wraps around inline content.
+func unwrapParagraph(html string) string {
+	trimmed := strings.TrimSpace(html)
+
+	if strings.HasPrefix(trimmed, "<p>") && strings.HasSuffix(trimmed, "</p>") && strings.Count(trimmed, "<p>") == 1 {
+		return strings.TrimSuffix(strings.TrimPrefix(trimmed, "<p>"), "</p>")
+	}
+
+	return trimmed
+}
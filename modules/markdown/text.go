@@ -0,0 +1,93 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	preBlockPattern = regexp.MustCompile(`(?s)<figure data-rehype-pretty-code-figure.*?</figure>|<pre[\s>].*?</pre>`)
+	headingPattern  = regexp.MustCompile(`(?s)<h([1-6])([^>]*)>(.*?)</h[1-6]>`)
+	idPattern       = regexp.MustCompile(`\bid="([^"]*)"`)
+	anchorPattern   = regexp.MustCompile(`(?s)<a class="anchor"[^>]*>.*?</a>`)
+	tagPattern      = regexp.MustCompile(`(?s)<[^>]*>`)
+	spacePattern    = regexp.MustCompile(`\s+`)
+	paragraphs      = regexp.MustCompile(`(?s)<p>(.*?)</p>`)
+)
+
+// EXCERPT_LENGTH is roughly how many characters excerpts are cut to.
+const EXCERPT_LENGTH = 160
+
+// stripTags turns an HTML fragment into collapsed plain text.
+func stripTags(fragment string) string {
+	text := tagPattern.ReplaceAllString(fragment, " ")
+	text = html.UnescapeString(text)
+
+	return strings.TrimSpace(spacePattern.ReplaceAllString(text, " "))
+}
+
+// plainText returns the readable text of rendered HTML. Code blocks are left
+// out, and so are JSX props since only their rendered output is left.
+func plainText(rendered string) string {
+	rendered = preBlockPattern.ReplaceAllString(rendered, " ")
+
+	return stripTags(anchorPattern.ReplaceAllString(rendered, ""))
+}
+
+// headings returns every heading of rendered HTML.
+func headings(rendered string) []Heading {
+	var found []Heading
+
+	for _, match := range headingPattern.FindAllStringSubmatch(rendered, -1) {
+		title := stripTags(anchorPattern.ReplaceAllString(match[3], ""))
+		if title == "" {
+			continue
+		}
+
+		heading := Heading{Level: int(match[1][0] - '0'), Title: title}
+
+		if id := idPattern.FindStringSubmatch(match[2]); id != nil {
+			heading.ID = html.UnescapeString(id[1])
+		}
+
+		found = append(found, heading)
+	}
+
+	return found
+}
+
+// excerpt returns the text of the first paragraph of rendered HTML, cut at a
+// word boundary when it's longer than EXCERPT_LENGTH.
+func excerpt(rendered string) string {
+	rendered = preBlockPattern.ReplaceAllString(rendered, " ")
+
+	for _, match := range paragraphs.FindAllStringSubmatch(rendered, -1) {
+		// Inline tags are dropped without a space so punctuation stays in place
+		text := html.UnescapeString(tagPattern.ReplaceAllString(match[1], ""))
+		text = strings.TrimSpace(spacePattern.ReplaceAllString(text, " "))
+
+		if text == "" {
+			continue
+		}
+
+		if len(text) <= EXCERPT_LENGTH {
+			return text
+		}
+
+		cut := strings.LastIndexByte(text[:EXCERPT_LENGTH], ' ')
+		if cut <= 0 {
+			cut = EXCERPT_LENGTH
+
+			// Never cut a multi-byte character in half
+			for cut > 0 && !utf8.RuneStart(text[cut]) {
+				cut--
+			}
+		}
+
+		return strings.TrimRight(text[:cut], ",.;:") + "…"
+	}
+
+	return ""
+}
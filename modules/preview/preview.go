@@ -0,0 +1,65 @@
+package preview
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"coding-kittens.com/modules/config"
+)
+
+// DEFAULT_TTL is how long preview links stay valid unless told otherwise.
+const DEFAULT_TTL = 7 * 24 * time.Hour
+
+// QUERY_PARAMETER carries the token of a preview link.
+const QUERY_PARAMETER = "preview"
+
+func signature(secret string, path string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(path + "\n" + strconv.FormatInt(expires, 10)))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Token signs path until expires. It returns "" when no secret is configured.
+func Token(path string, expires time.Time) string {
+	secret := config.Get().PreviewSecret
+	if secret == "" {
+		return ""
+	}
+
+	return strconv.FormatInt(expires.Unix(), 10) + "." + signature(secret, path, expires.Unix())
+}
+
+// URL returns the absolute preview link of path, valid for ttl.
+func URL(path string, ttl time.Duration) string {
+	token := Token(path, time.Now().Add(ttl))
+	if token == "" {
+		return ""
+	}
+
+	return config.Get().AbsoluteURL(path) + "?" + QUERY_PARAMETER + "=" + token
+}
+
+// Verify reports whether token is a valid, unexpired signature of path.
+func Verify(path string, token string) bool {
+	secret := config.Get().PreviewSecret
+	if secret == "" || token == "" {
+		return false
+	}
+
+	expiresValue, signed, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+
+	expires, err := strconv.ParseInt(expiresValue, 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return false
+	}
+
+	return hmac.Equal([]byte(signed), []byte(signature(secret, path, expires)))
+}
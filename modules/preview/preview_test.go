@@ -0,0 +1,76 @@
+package preview
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"coding-kittens.com/modules/config"
+)
+
+func TestVerify(t *testing.T) {
+	t.Setenv("PREVIEW_SECRET", "secret")
+	config.Load("https://coding-kittens.com", "test")
+
+	path := "/blog/css/draft"
+	valid := Token(path, time.Now().Add(time.Hour))
+	expires, signed, _ := strings.Cut(valid, ".")
+
+	tests := []struct {
+		name  string
+		path  string
+		token string
+		want  bool
+	}{
+		{name: "valid", path: path, token: valid, want: true},
+		{name: "other path", path: "/blog/css/other", token: valid},
+		{name: "expired", path: path, token: Token(path, time.Now().Add(-time.Minute))},
+		{name: "extended expiry", path: path, token: strconv.FormatInt(time.Now().Add(48*time.Hour).Unix(), 10) + "." + signed},
+		{name: "tampered signature", path: path, token: expires + "." + strings.ToUpper(signed)},
+		{name: "no signature", path: path, token: expires},
+		{name: "malformed expiry", path: path, token: "soon." + signed},
+		{name: "empty", path: path, token: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Verify(test.path, test.token); got != test.want {
+				t.Errorf("Verify(%q, %q) = %v, want %v", test.path, test.token, got, test.want)
+			}
+		})
+	}
+}
+
+func TestURL(t *testing.T) {
+	t.Setenv("PREVIEW_SECRET", "secret")
+	config.Load("https://coding-kittens.com", "test")
+
+	link, err := url.Parse(URL("/blog/css/draft", DEFAULT_TTL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if link.Host != "coding-kittens.com" || link.Path != "/blog/css/draft" || !Verify(link.Path, link.Query().Get(QUERY_PARAMETER)) {
+		t.Errorf("URL() = %s, want a valid link to the article", link)
+	}
+}
+
+func TestWithoutSecret(t *testing.T) {
+	t.Setenv("PREVIEW_SECRET", "")
+	config.Load("https://coding-kittens.com", "test")
+
+	// Nothing can be previewed until a secret is configured
+	if token := Token("/blog/css/draft", time.Now().Add(time.Hour)); token != "" {
+		t.Errorf("Token() = %q, want none", token)
+	}
+
+	if URL("/blog/css/draft", DEFAULT_TTL) != "" {
+		t.Error("URL() signed a link without a secret")
+	}
+
+	if Verify("/blog/css/draft", "1.abc") {
+		t.Error("Verify() accepted a token without a secret")
+	}
+}
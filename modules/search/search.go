@@ -0,0 +1,228 @@
+package search
+
+import (
+	"html/template"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Field weights: a match in the title counts more than one in the body.
+const (
+	TITLE_WEIGHT       = 5.0
+	HEADING_WEIGHT     = 3.0
+	DESCRIPTION_WEIGHT = 2.0
+	BODY_WEIGHT        = 1.0
+)
+
+// Match weights depending on how a query word was found in the vocabulary.
+const (
+	EXACT_MATCH  = 1.0
+	PREFIX_MATCH = 0.8
+	TYPO_MATCH   = 0.6
+)
+
+// Limits of a query, as every word of it may be matched against the whole
+// vocabulary.
+const (
+	MAX_QUERY_LENGTH = 200 // bytes
+	MAX_QUERY_WORDS  = 8
+)
+
+// Document is the searchable content of an article.
+type Document struct {
+	Title       string
+	Headings    []string
+	Description string
+	Body        string
+}
+
+// Result is a document matching a query, with a highlighted excerpt.
+type Result struct {
+	Document int // index of the document in the slice given to NewIndex
+	Score    float64
+	Snippet  template.HTML
+}
+
+// Index is an inverted index of a fixed set of documents.
+type Index struct {
+	documents  []Document
+	postings   map[string]map[int]float64
+	vocabulary []string // every term, sorted for prefix lookups
+}
+
+// NewIndex indexes documents, which are then referred to by their position.
+func NewIndex(documents []Document) *Index {
+	index := &Index{
+		documents: documents,
+		postings:  map[string]map[int]float64{},
+	}
+
+	for i, document := range documents {
+		index.add(i, document.Title, TITLE_WEIGHT)
+		index.add(i, document.Description, DESCRIPTION_WEIGHT)
+		index.add(i, document.Body, BODY_WEIGHT)
+
+		for _, heading := range document.Headings {
+			index.add(i, heading, HEADING_WEIGHT)
+		}
+	}
+
+	for term := range index.postings {
+		index.vocabulary = append(index.vocabulary, term)
+	}
+
+	sort.Strings(index.vocabulary)
+
+	return index
+}
+
+func (index *Index) add(document int, text string, weight float64) {
+	for _, term := range Terms(text) {
+		if index.postings[term] == nil {
+			index.postings[term] = map[int]float64{}
+		}
+
+		index.postings[term][document] += weight
+	}
+}
+
+// maxTypos is how many edits a query word of the given length tolerates.
+func maxTypos(word string) int {
+	switch {
+	case len(word) >= 8:
+		return 2
+	case len(word) >= 4:
+		return 1
+	}
+
+	return 0
+}
+
+// expand returns the vocabulary terms a query word matches, with the weight
+// of each match. The last word of a query is also matched as a prefix since
+// the user is probably still typing it.
+func (index *Index) expand(word string, prefix bool) map[string]float64 {
+	matches := map[string]float64{}
+	term := stem(word)
+
+	if _, ok := index.postings[term]; ok {
+		matches[term] = EXACT_MATCH
+	}
+
+	if prefix {
+		start := sort.SearchStrings(index.vocabulary, term)
+
+		for _, candidate := range index.vocabulary[start:] {
+			if !strings.HasPrefix(candidate, term) {
+				break
+			}
+
+			if _, ok := matches[candidate]; !ok {
+				matches[candidate] = PREFIX_MATCH
+			}
+		}
+	}
+
+	if len(matches) > 0 {
+		return matches
+	}
+
+	if typos := maxTypos(term); typos > 0 {
+		for _, candidate := range index.vocabulary {
+			if distance(term, candidate, typos) <= typos {
+				matches[candidate] = TYPO_MATCH
+			}
+		}
+	}
+
+	return matches
+}
+
+// Search returns up to limit documents containing every word of query, best
+// matches first.
+func (index *Index) Search(query string, limit int) []Result {
+	if len(query) > MAX_QUERY_LENGTH {
+		end := MAX_QUERY_LENGTH
+		for end > 0 && !utf8.RuneStart(query[end]) {
+			end--
+		}
+
+		query = query[:end]
+	}
+
+	var words []string
+
+	for _, t := range tokenize(query) {
+		if len(words) == MAX_QUERY_WORDS {
+			break
+		}
+
+		if !stopWords[t.word] {
+			words = append(words, t.word)
+		}
+	}
+
+	if len(words) == 0 {
+		return nil
+	}
+
+	var scores map[int]float64
+	var matched []string
+
+	for i, word := range words {
+		wordScores := map[int]float64{}
+
+		for term, weight := range index.expand(word, i == len(words)-1) {
+			postings := index.postings[term]
+			idf := math.Log(1 + float64(len(index.documents))/float64(len(postings)))
+
+			for document, frequency := range postings {
+				score := weight * idf * (1 + math.Log(frequency))
+				wordScores[document] = max(wordScores[document], score)
+			}
+
+			matched = append(matched, term)
+		}
+
+		// Every word has to match
+		if scores == nil {
+			scores = wordScores
+			continue
+		}
+
+		for document := range scores {
+			if score, ok := wordScores[document]; ok {
+				scores[document] += score
+			} else {
+				delete(scores, document)
+			}
+		}
+	}
+
+	results := make([]Result, 0, len(scores))
+
+	for document, score := range scores {
+		results = append(results, Result{Document: document, Score: score})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+
+		return results[i].Document < results[j].Document
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	for i := range results {
+		document := index.documents[results[i].Document]
+		results[i].Snippet = snippet(document.Description+" "+document.Body, matched)
+	}
+
+	return results
+}
@@ -0,0 +1,54 @@
+package search
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	index := NewIndex([]Document{
+		{Title: "Recursion limits", Body: "The stack size of recursive functions"},
+		{Title: "Navbar", Headings: []string{"Scroll animations"}, Body: "Modern CSS features"},
+		{Title: "Variables", Description: "Custom properties", Body: "CSS variables and functions"},
+	})
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{query: "", want: nil},
+		{query: "the", want: nil},
+		{query: "recursion", want: []int{0}},
+		{query: "css", want: []int{1, 2}},
+		{query: "css variables", want: []int{2}},
+		{query: "functions", want: []int{0, 2}},
+		{query: "scrol", want: []int{1}},
+		{query: "recurson", want: []int{0}},
+		{query: "css nothing", want: nil},
+		{
+			// Words past the limit are left out
+			query: "recursion" + strings.Repeat(" stack", MAX_QUERY_WORDS-1) + " nothing",
+			want:  []int{0},
+		},
+		{
+			// So is the end of a query past the length limit
+			query: "recursion" + strings.Repeat(" ", MAX_QUERY_LENGTH) + "nothing",
+			want:  []int{0},
+		},
+		{query: strings.Repeat("ñ", MAX_QUERY_LENGTH), want: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			var got []int
+			for _, result := range index.Search(test.query, 10) {
+				got = append(got, result.Document)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Search(%q) = %v, want %v", test.query, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package search
+
+import (
+	"html"
+	"html/template"
+	"strings"
+	"unicode/utf8"
+)
+
+// SNIPPET_RADIUS is roughly how many characters of context are kept on each
+// side of the first match.
+const SNIPPET_RADIUS = 80
+
+// snippet cuts a window of text around the first word matching one of the
+// given terms, and wraps every matching word of the window in <mark>.
+func snippet(text string, matched []string) template.HTML {
+	isMatch := func(word string) bool {
+		term := stem(word)
+
+		for _, m := range matched {
+			if term == m {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	tokens := tokenize(text)
+
+	first := -1
+	for _, t := range tokens {
+		if isMatch(t.word) {
+			first = t.start
+			break
+		}
+	}
+
+	start, end := 0, len(text)
+
+	if first >= 0 {
+		start = max(first-SNIPPET_RADIUS, 0)
+		end = min(first+SNIPPET_RADIUS, len(text))
+	} else {
+		end = min(2*SNIPPET_RADIUS, len(text))
+	}
+
+	// Never cut a multi-byte character in half
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
+	}
+
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end--
+	}
+
+	// Move the edges of the window to word boundaries
+	if start > 0 {
+		if space := strings.IndexByte(text[start:], ' '); space >= 0 && start+space < first {
+			start += space + 1
+		}
+	}
+
+	if end < len(text) {
+		if space := strings.LastIndexByte(text[:end], ' '); space > max(first, start) {
+			end = space
+		}
+	}
+
+	var sb strings.Builder
+
+	if start > 0 {
+		sb.WriteString("… ")
+	}
+
+	position := start
+
+	for _, t := range tokens {
+		if t.start < start || t.end > end || !isMatch(t.word) {
+			continue
+		}
+
+		sb.WriteString(html.EscapeString(text[position:t.start]))
+		sb.WriteString("<mark>")
+		sb.WriteString(html.EscapeString(text[t.start:t.end]))
+		sb.WriteString("</mark>")
+
+		position = t.end
+	}
+
+	sb.WriteString(html.EscapeString(text[position:end]))
+
+	if end < len(text) {
+		sb.WriteString(" …")
+	}
+
+	return template.HTML(sb.String())
+}
@@ -0,0 +1,79 @@
+package search
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSnippet(t *testing.T) {
+	long := strings.Repeat("word ", 40)
+
+	tests := []struct {
+		name   string
+		text   string
+		terms  []string
+		want   string
+		prefix bool // cut before the window
+		suffix bool // cut after the window
+	}{
+		{
+			name:  "short text",
+			text:  "Fixing recursion limits",
+			terms: []string{"recurs"},
+			want:  "Fixing <mark>recursion</mark> limits",
+		},
+		{
+			name:   "match in the middle",
+			text:   long + "recursion " + long,
+			terms:  []string{"recurs"},
+			want:   "<mark>recursion</mark>",
+			prefix: true,
+			suffix: true,
+		},
+		{
+			name:   "no match",
+			text:   long + long,
+			terms:  []string{"recurs"},
+			suffix: true,
+		},
+		{
+			name:  "escaped text",
+			text:  "a <b> recursion",
+			terms: []string{"recurs"},
+			want:  "a &lt;b&gt; <mark>recursion</mark>",
+		},
+		{
+			// Without spaces near the edges the window falls inside the
+			// multi-byte characters
+			name:   "multi-byte characters",
+			text:   strings.Repeat("ñ", 100) + " recursion " + strings.Repeat("€", 100),
+			terms:  []string{"recurs"},
+			want:   "<mark>recursion</mark>",
+			prefix: true,
+			suffix: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := string(snippet(test.text, test.terms))
+
+			if !utf8.ValidString(got) {
+				t.Fatalf("snippet() = %q, which isn't valid UTF-8", got)
+			}
+
+			if !strings.Contains(got, test.want) {
+				t.Errorf("snippet() = %q, want it to contain %q", got, test.want)
+			}
+
+			if prefix := strings.HasPrefix(got, "… "); prefix != test.prefix {
+				t.Errorf("snippet() = %q, cut before = %v, want %v", got, prefix, test.prefix)
+			}
+
+			if suffix := strings.HasSuffix(got, " …"); suffix != test.suffix {
+				t.Errorf("snippet() = %q, cut after = %v, want %v", got, suffix, test.suffix)
+			}
+		})
+	}
+}
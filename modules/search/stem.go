@@ -0,0 +1,161 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "but": true, "by": true, "for": true, "from": true, "if": true,
+	"in": true, "into": true, "is": true, "it": true, "its": true, "of": true,
+	"on": true, "or": true, "so": true, "than": true, "that": true, "the": true,
+	"then": true, "this": true, "to": true, "was": true, "we": true, "with": true,
+	"you": true, "your": true, "i": true, "my": true,
+}
+
+// suffixes are stripped by stem, longest first.
+var suffixes = []string{
+	"ational", "fulness", "iveness", "ization",
+	"ations", "ements",
+	"ation", "ative", "ement", "ingly", "ments",
+	"able", "ible", "ment", "ness", "edly",
+	"ing", "ion", "ive", "ize", "ise", "ity", "ous", "ful",
+	"al", "ed", "er", "ly",
+}
+
+func isVowel(r byte) bool {
+	return strings.IndexByte("aeiouy", r) >= 0
+}
+
+// stem reduces an English word to a crude root, so "recursion", "recursive"
+// and "recursively" all end up as "recurs". It only has to be consistent
+// between the index and the queries, not linguistically correct.
+func stem(word string) string {
+	if len(word) <= 3 {
+		return word
+	}
+
+	switch {
+	case strings.HasSuffix(word, "sses"):
+		word = word[:len(word)-2]
+	case strings.HasSuffix(word, "ies") && len(word) > 4:
+		word = word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "ss") || strings.HasSuffix(word, "us") || strings.HasSuffix(word, "is"):
+	case strings.HasSuffix(word, "s"):
+		word = word[:len(word)-1]
+	}
+
+	if strings.HasSuffix(word, "ly") && len(word) > 5 {
+		word = word[:len(word)-2]
+	}
+
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(word, suffix) && len(word)-len(suffix) >= 3 {
+			word = word[:len(word)-len(suffix)]
+			break
+		}
+	}
+
+	// Undouble the final consonant: "running" → "runn" → "run"
+	if n := len(word); n > 3 && word[n-1] == word[n-2] && !isVowel(word[n-1]) && strings.IndexByte("lsz", word[n-1]) < 0 {
+		word = word[:n-1]
+	}
+
+	return word
+}
+
+// token is a word of a text along with its byte offsets.
+type token struct {
+	word  string
+	start int
+	end   int
+}
+
+// tokenize splits text into lowercase words.
+func tokenize(text string) []token {
+	var tokens []token
+
+	start := -1
+
+	flush := func(end int) {
+		if start >= 0 {
+			tokens = append(tokens, token{word: strings.ToLower(text[start:end]), start: start, end: end})
+			start = -1
+		}
+	}
+
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+		} else {
+			flush(i)
+		}
+	}
+
+	flush(len(text))
+
+	return tokens
+}
+
+// Terms returns the stemmed index terms of text, without stop words.
+func Terms(text string) []string {
+	var result []string
+
+	for _, t := range tokenize(text) {
+		if stopWords[t.word] {
+			continue
+		}
+
+		result = append(result, stem(t.word))
+	}
+
+	return result
+}
+
+// distance is the Levenshtein distance between a and b, giving up early once
+// it exceeds max.
+func distance(a string, b string, max int) int {
+	if a == b {
+		return 0
+	}
+
+	if diff := len(a) - len(b); diff > max || -diff > max {
+		return max + 1
+	}
+
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		best := current[0]
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+
+			if current[j] < best {
+				best = current[j]
+			}
+		}
+
+		if best > max {
+			return max + 1
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
@@ -0,0 +1,90 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		want []token
+	}{
+		{text: "", want: nil},
+		{text: " ,.! ", want: nil},
+		{
+			text: "Stack-size limit",
+			want: []token{{"stack", 0, 5}, {"size", 6, 10}, {"limit", 11, 16}},
+		},
+		{
+			text: "CSS3 and HTML5",
+			want: []token{{"css3", 0, 4}, {"and", 5, 8}, {"html5", 9, 14}},
+		},
+		{
+			// Offsets are in bytes, so multi-byte letters widen the words
+			text: "¡Diseño web!",
+			want: []token{{"diseño", 2, 9}, {"web", 10, 13}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			if got := tokenize(test.text); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("tokenize(%q) = %+v, want %+v", test.text, got, test.want)
+			}
+		})
+	}
+}
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		words []string
+		want  string
+	}{
+		{words: []string{"recursion", "recursive", "recursively"}, want: "recurs"},
+		{words: []string{"run", "runs", "running"}, want: "run"},
+		{words: []string{"scroll", "scrolled", "scrolling"}, want: "scroll"},
+		{words: []string{"iteration", "iterations"}, want: "iter"},
+		{words: []string{"function", "functions"}, want: "funct"},
+		{words: []string{"animation", "animations"}, want: "anim"},
+		{words: []string{"class", "classes"}, want: "class"},
+		{words: []string{"story", "stories"}, want: "story"},
+		{words: []string{"status"}, want: "status"},
+		{words: []string{"analysis"}, want: "analysis"},
+		{words: []string{"buzz"}, want: "buzz"},
+		{words: []string{"css"}, want: "css"},
+		{words: []string{"go"}, want: "go"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.want, func(t *testing.T) {
+			for _, word := range test.words {
+				if got := stem(word); got != test.want {
+					t.Errorf("stem(%q) = %q, want %q", word, got, test.want)
+				}
+			}
+		})
+	}
+}
+
+func TestTerms(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{text: "", want: nil},
+		{text: "the and of", want: nil},
+		{
+			text: "The recursion of stack-size limits in JavaScript functions",
+			want: []string{"recurs", "stack", "size", "limit", "javascript", "funct"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			if got := Terms(test.text); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Terms(%q) = %q, want %q", test.text, got, test.want)
+			}
+		})
+	}
+}
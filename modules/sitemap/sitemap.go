@@ -0,0 +1,97 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// Limits of a single sitemap file set by the sitemaps.org protocol.
+const (
+	MAX_URLS  = 50000
+	MAX_BYTES = 50 * 1024 * 1024
+)
+
+const XMLNS = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// URL is an entry of a sitemap or of a sitemap index.
+type URL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// Entry returns the sitemap entry of an absolute URL.
+func Entry(loc string, lastModified time.Time) URL {
+	url := URL{Loc: loc}
+
+	if !lastModified.IsZero() {
+		url.LastMod = lastModified.UTC().Format(time.RFC3339)
+	}
+
+	return url
+}
+
+type urlSet struct {
+	XMLName xml.Name `xml:"urlset"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	URLs    []URL    `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name `xml:"sitemapindex"`
+	XMLNS    string   `xml:"xmlns,attr"`
+	Sitemaps []URL    `xml:"sitemap"`
+}
+
+// envelope is the size of everything in a sitemap file but its entries,
+// with some room to spare.
+const envelope = 1024
+
+func size(url URL) int {
+	encoded, _ := xml.Marshal(url)
+
+	// <url> instead of <URL>, plus indentation
+	return len(encoded) + 8
+}
+
+// Paginate splits urls into as many sitemaps as needed to stay under the
+// protocol limits.
+func Paginate(urls []URL) [][]URL {
+	var pages [][]URL
+	var page []URL
+
+	bytes := envelope
+
+	for _, url := range urls {
+		entrySize := size(url)
+
+		if len(page) == MAX_URLS || (len(page) > 0 && bytes+entrySize > MAX_BYTES) {
+			pages = append(pages, page)
+			page = nil
+			bytes = envelope
+		}
+
+		page = append(page, url)
+		bytes += entrySize
+	}
+
+	return append(pages, page)
+}
+
+func encode(v interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), body...), nil
+}
+
+// URLSet encodes a sitemap listing urls.
+func URLSet(urls []URL) ([]byte, error) {
+	return encode(urlSet{XMLNS: XMLNS, URLs: urls})
+}
+
+// Index encodes a sitemap index pointing at the given sitemaps.
+func Index(sitemaps []URL) ([]byte, error) {
+	return encode(sitemapIndex{XMLNS: XMLNS, Sitemaps: sitemaps})
+}
@@ -0,0 +1,101 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func urls(count int, loc string) []URL {
+	list := make([]URL, count)
+	for i := range list {
+		list[i] = URL{Loc: fmt.Sprintf("%s/%d", loc, i)}
+	}
+
+	return list
+}
+
+func TestEntry(t *testing.T) {
+	madrid := time.FixedZone("CET", 3600)
+
+	if got := Entry("https://coding-kittens.com/blog", time.Date(2024, 1, 20, 10, 0, 0, 0, madrid)); got.LastMod != "2024-01-20T09:00:00Z" {
+		t.Errorf("Entry() = %+v, want the date in UTC", got)
+	}
+
+	if got := Entry("https://coding-kittens.com/blog", time.Time{}); got.LastMod != "" {
+		t.Errorf("Entry() = %+v, want no date", got)
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	// Long enough for a few thousand to go over the size limit
+	long := "https://coding-kittens.com/" + strings.Repeat("a", 40000)
+
+	tests := []struct {
+		name string
+		urls []URL
+		want []int // size of every page
+	}{
+		{name: "empty", urls: nil, want: []int{0}},
+		{name: "one page", urls: urls(10, "https://coding-kittens.com"), want: []int{10}},
+		{name: "full page", urls: urls(MAX_URLS, "https://coding-kittens.com"), want: []int{MAX_URLS}},
+		{name: "count limit", urls: urls(MAX_URLS+1, "https://coding-kittens.com"), want: []int{MAX_URLS, 1}},
+		{name: "size limit", urls: urls(1400, long), want: []int{1308, 92}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pages := Paginate(test.urls)
+
+			var got []int
+			total := 0
+
+			for _, page := range pages {
+				got = append(got, len(page))
+				total += len(page)
+
+				if body, _ := URLSet(page); len(body) > MAX_BYTES {
+					t.Errorf("page of %d bytes, over the %d bytes limit", len(body), MAX_BYTES)
+				}
+			}
+
+			if fmt.Sprint(got) != fmt.Sprint(test.want) || total != len(test.urls) {
+				t.Errorf("Paginate() pages = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestURLSet(t *testing.T) {
+	body, err := URLSet([]URL{{Loc: "https://coding-kittens.com/?a=1&b=2", LastMod: "2024-01-20T09:00:00Z"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded urlSet
+	if err := xml.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("URLSet() isn't valid XML: %v", err)
+	}
+
+	if decoded.XMLName.Space != XMLNS || len(decoded.URLs) != 1 || decoded.URLs[0].Loc != "https://coding-kittens.com/?a=1&b=2" {
+		t.Errorf("URLSet() = %s", body)
+	}
+}
+
+func TestIndex(t *testing.T) {
+	body, err := Index([]URL{{Loc: "https://coding-kittens.com/sitemaps/1.xml"}, {Loc: "https://coding-kittens.com/sitemaps/2.xml"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded sitemapIndex
+	if err := xml.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("Index() isn't valid XML: %v", err)
+	}
+
+	if decoded.XMLName.Local != "sitemapindex" || len(decoded.Sitemaps) != 2 {
+		t.Errorf("Index() = %s", body)
+	}
+}
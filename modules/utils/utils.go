@@ -1,18 +1,19 @@
 package utils
 
 import (
-	"embed"
+	"io/fs"
 	"regexp"
 	"strings"
 )
 
-var StaticAssets embed.FS
+// StaticAssets holds the static files, assigned on startup.
+var StaticAssets fs.FS
 
-const CSS_PATH = "web/static/css/styles.css"
+const CSS_PATH = "css/styles.css"
 
 func GetAccentBaseValue() string {
 	// Read the file synchronously
-	fileContent, err := StaticAssets.ReadFile(CSS_PATH)
+	fileContent, err := fs.ReadFile(StaticAssets, CSS_PATH)
 	if err != nil {
 		// Handle error, e.g., log or return an error value
 		return ""
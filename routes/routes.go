@@ -4,7 +4,12 @@ import "coding-kittens.com/controllers"
 
 type RouteData struct {
 	Title   string // metadata title
+	Description string // metadata description
 	Content string // template name to be used. for example for "about.tmpl" Content is equal to "about"
+	Partial string // template rendered on its own for htmx requests that aren't boosted navigations
+	Canonical string // path of the canonical URL, "" for the path of the page itself
+	Robots string // robots directives, e.g. "noindex, follow", "" to let crawlers index the page
+	OpenGraphType string // Open Graph type of the page, "website" when empty
 	Controller controllers.ControllerFunc // controller function to send data to the template
 }
 
@@ -12,12 +17,48 @@ func GetRoutes() map[string]RouteData {
 	return map[string]RouteData{
 		"/": {
 			Title:      "Home Page",
+			Description: "Coding Kittens, a blog about web development: CSS, JavaScript and everything in between",
 			Content:    "about",
+			OpenGraphType: "profile",
 			Controller: controllers.AboutController,
 		},
 		"/blog": {
-			Title:   "Blog",
-			Content: "blog",
+			Title:      "Blog",
+			Description: "Every Coding Kittens post, newest first",
+			Content:    "blog",
+			Partial:    "blog_fragment",
+			Controller: controllers.BlogController,
+		},
+		"/tags": {
+			Title:      "Tags",
+			Description: "The Coding Kittens posts by tag",
+			Content:    "tags",
+			Controller: controllers.TagsController,
+		},
+		"/tags/:tag": {
+			Title:      "Tags",
+			Content:    "tag",
+			Partial:    "blog_fragment",
+			Controller: controllers.TagController,
+		},
+		"/series/:name": {
+			Title:      "Series",
+			Content:    "series",
+			Controller: controllers.SeriesController,
+		},
+		"/search": {
+			Title:      "Search",
+			Description: "Search the Coding Kittens posts",
+			Content:    "search",
+			Partial:    "search_results",
+			Canonical:  "/search",
+			Robots:     "noindex, follow",
+			Controller: controllers.SearchController,
+		},
+		"/blog/*path": {
+			Title:      "Blog",
+			Content:    "blog_path",
+			Controller: controllers.BlogPathController,
 		},
 	}
 }
\ No newline at end of file